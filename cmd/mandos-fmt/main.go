@@ -0,0 +1,161 @@
+// mandos-fmt rewrites mandos scenario files in a canonical style.
+//
+// Usage:
+//
+//	mandos-fmt [flags] <file or directory>...
+//
+// Directories are searched recursively for *.scen.json and *.steps.json files.
+// Files are rewritten in place, unless --check is given: then the files that are not formatted get listed
+// and the exit code is 1, which makes it usable in CI.
+// Comments and trailing commas are accepted, comments are kept.
+// Files with duplicate map keys are reported as errors and left untouched, since formatting would drop the duplicates.
+//
+// The default style is the one of the mandos JSON writer: four space indentation, every element on its own line.
+// Files written by the repo tools are therefore already formatted.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
+)
+
+const (
+	exitOK          = 0
+	exitUnformatted = 1
+	exitError       = 2
+)
+
+var errDuplicateKeys = errors.New("duplicate keys")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command with the given arguments and yields the exit code.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("mandos-fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	indentWidth := flags.Int("indent", 4, "number of spaces per indentation level")
+	useTabs := flags.Bool("tabs", false, "indent with tabs instead of spaces")
+	compactWidth := flags.Int("compact-width", 0, "write lists and maps that fit in this many characters on one line, 0 to disable")
+	sortKeys := flags.Bool("sort-keys", false, "sort map keys alphabetically")
+	minify := flags.Bool("minify", false, "write without any whitespace, drops comments")
+	check := flags.Bool("check", false, "only list the files that are not formatted, exit with code 1 if there are any")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: mandos-fmt [flags] <file or directory>...\n")
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return exitError
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	options := oj.FormatOptions{
+		Indent:       strings.Repeat(" ", *indentWidth),
+		CompactWidth: *compactWidth,
+		SortKeys:     *sortKeys,
+		Minify:       *minify,
+	}
+	if *useTabs {
+		options.Indent = "\t"
+	}
+
+	exitCode := exitOK
+	for _, path := range flags.Args() {
+		files, err := scenarioFiles(path)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			exitCode = exitError
+			continue
+		}
+		for _, file := range files {
+			formatted, err := formatFile(file, options, *check)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", file, err)
+				exitCode = exitError
+				continue
+			}
+			if !formatted && *check {
+				fmt.Fprintln(stdout, file)
+				if exitCode == exitOK {
+					exitCode = exitUnformatted
+				}
+			}
+		}
+	}
+	return exitCode
+}
+
+// scenarioFiles yields the path itself for files, and the scenario files in it for directories.
+func scenarioFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && (strings.HasSuffix(filePath, ".scen.json") || strings.HasSuffix(filePath, ".steps.json")) {
+			files = append(files, filePath)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// formatFile yields true if the file was already formatted. Otherwise it rewrites it, unless only checking.
+func formatFile(path string, options oj.FormatOptions, checkOnly bool) (bool, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	decoder := oj.NewBytesDecoder(contents)
+	decoder.Relaxed = true
+	// collect all duplicates, to report them at once
+	decoder.AllowDuplicateKeys = true
+	jobj, err := decoder.Decode()
+	if err != nil {
+		return false, err
+	}
+	// the decoder only keeps the first value of duplicate keys, writing the file would lose the others
+	if len(decoder.Warnings) > 0 {
+		messages := make([]string, len(decoder.Warnings))
+		for i, warning := range decoder.Warnings {
+			messages[i] = warning.Error()
+		}
+		return false, fmt.Errorf("%w, not formatting: %s", errDuplicateKeys, strings.Join(messages, "; "))
+	}
+
+	options.Comments = decoder.Comments
+	formatted := []byte(oj.JSONStringWithOptions(jobj, options))
+	if bytes.Equal(contents, formatted) {
+		return true, nil
+	}
+	if checkOnly {
+		return false, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	return false, os.WriteFile(path, formatted, info.Mode().Perm())
+}
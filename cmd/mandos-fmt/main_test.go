@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjwrite "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/write"
+	"github.com/stretchr/testify/require"
+)
+
+func runForTest(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	exitCode := run(args, &stdout, &stderr)
+	return exitCode, stdout.String(), stderr.String()
+}
+
+func TestFormat(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.scen.json")
+	input := `{"name": "test",
+  // the steps
+  "steps": [{"step": "setState",}, {"step": "checkState", "accounts": {}}]}`
+	require.Nil(t, os.WriteFile(path, []byte(input), 0644))
+
+	exitCode, stdout, _ := runForTest("-check", dir)
+	require.Equal(t, exitUnformatted, exitCode)
+	require.Equal(t, path+"\n", stdout)
+	contents, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, input, string(contents))
+
+	exitCode, _, _ = runForTest(dir)
+	require.Equal(t, exitOK, exitCode)
+	contents, err = os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, `{
+    "name": "test",
+    // the steps
+    "steps": [
+        {
+            "step": "setState"
+        },
+        {
+            "step": "checkState",
+            "accounts": {}
+        }
+    ]
+}
+`, string(contents))
+
+	exitCode, stdout, _ = runForTest("-check", dir)
+	require.Equal(t, exitOK, exitCode)
+	require.Empty(t, stdout)
+}
+
+func TestFormatKeepsListComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.scen.json")
+	input := "{\"steps\": [ // no steps yet\n]}"
+	require.Nil(t, os.WriteFile(path, []byte(input), 0644))
+
+	exitCode, _, _ := runForTest(path)
+	require.Equal(t, exitOK, exitCode)
+	contents, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, "{\n    \"steps\": [\n        // no steps yet\n    ]\n}\n", string(contents))
+}
+
+func TestFormatWriterOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "written.scen.json")
+	scenario := &mj.Scenario{
+		Name: "written",
+		Steps: []mj.Step{
+			&mj.SetStateStep{Comment: "set"},
+			&mj.TxStep{TxIdent: "1", Tx: &mj.Transaction{Type: mj.ScCall, Function: "f"}},
+		},
+	}
+	require.Nil(t, os.WriteFile(path, []byte(mjwrite.ScenarioToJSONString(scenario)), 0644))
+
+	exitCode, stdout, stderr := runForTest("-check", path)
+	require.Equal(t, exitOK, exitCode, stderr)
+	require.Empty(t, stdout)
+}
+
+func TestFormatDuplicateKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "duplicate.scen.json")
+	input := `{"name": "first", "name": "second", "steps": []}`
+	require.Nil(t, os.WriteFile(path, []byte(input), 0644))
+
+	exitCode, _, stderr := runForTest(path)
+	require.Equal(t, exitError, exitCode)
+	require.Contains(t, stderr, "duplicate map key \"name\"")
+	contents, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, input, string(contents))
+}
+
+func TestFormatUsage(t *testing.T) {
+	exitCode, _, _ := runForTest()
+	require.Equal(t, exitError, exitCode)
+	exitCode, _, _ = runForTest("-unknown", ".")
+	require.Equal(t, exitError, exitCode)
+}
@@ -0,0 +1,226 @@
+// orderedjson2kast converts mandos scenario and test files to KAST, the format K reads its input in.
+//
+// Usage:
+//
+//	orderedjson2kast [flags] <file or directory>...
+//
+// Directories are searched recursively for *.scen.json, *.steps.json and *.test.json files.
+// Each file gets converted to a file with the same name plus the .kast extension, next to it or under --out.
+//
+// Code values of the form "file:<path>" can be processed before the conversion:
+// --assembler runs a command with the code file path as last argument and uses its output as the code,
+// --artifact-ext reads the code from the file next to the source that has this extension instead, e.g. a precompiled ".hex".
+// Without either, code values are kept as they are.
+//
+// The output is for the IELE semantics by default; --profile json targets the JSON module of the K standard library.
+//
+// Relative code paths are resolved against the directory of the test, absolute ones are used as they are.
+// The assembler command is split like a shell would, so arguments with spaces can be quoted.
+//
+// All files are processed even if some of them fail. The exit code is 1 if any of them failed, 2 for invalid arguments.
+// Inputs under different roots that would be written to the same output file are an error.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ojkast "github.com/kalyan3104/dme-vm-util/test-util/orderedjson2kast"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run executes the command with the given arguments and yields the exit code.
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("orderedjson2kast", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	outDir := flags.String("out", "", "directory to write the .kast files to, keeping the relative paths; by default they are written next to the inputs")
+	assembler := flags.String("assembler", "", "command that assembles a code file, e.g. \"iele-assemble --hex\"; the file path is appended and the output is used as code")
+	artifactExt := flags.String("artifact-ext", "", "read the code from the file next to the source with this extension instead, e.g. \".hex\"")
+	profileName := flags.String("profile", "iele", "KAST target profile: \"iele\" or \"json\"")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: orderedjson2kast [flags] <file or directory>...\n")
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	profile, err := ojkast.KastProfileByName(*profileName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	var processor codeProcessor
+	switch {
+	case *assembler != "" && *artifactExt != "":
+		fmt.Fprintln(stderr, "--assembler and --artifact-ext cannot be used together")
+		return exitUsage
+	case *assembler != "":
+		command, err := splitCommand(*assembler)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid --assembler: %v\n", err)
+			return exitUsage
+		}
+		processor = func(codePath string) (string, error) {
+			return runAssembler(command, codePath)
+		}
+	case *artifactExt != "":
+		processor = func(codePath string) (string, error) {
+			return readArtifact(codePath, *artifactExt)
+		}
+	}
+
+	exitCode := exitOK
+	// inputs by output path, different inputs must not overwrite each other's output
+	writtenFrom := make(map[string]string)
+	for _, root := range flags.Args() {
+		files, err := testFiles(root)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			exitCode = exitError
+			continue
+		}
+		for _, file := range files {
+			outPath, err := outputPath(root, file, *outDir)
+			if err == nil {
+				err = checkCollision(writtenFrom, file, outPath)
+			}
+			if err == errAlreadyConverted {
+				continue
+			}
+			if err == nil {
+				err = convertFile(file, outPath, processor, profile)
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", file, err)
+				exitCode = exitError
+			}
+		}
+	}
+	return exitCode
+}
+
+// testFiles yields the path itself for files, and the scenario and test files in it for directories.
+func testFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && isTestFile(filePath) {
+			files = append(files, filePath)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func isTestFile(path string) bool {
+	return strings.HasSuffix(path, ".scen.json") ||
+		strings.HasSuffix(path, ".steps.json") ||
+		strings.HasSuffix(path, ".test.json")
+}
+
+// outputPath yields where the KAST for a file goes. Under the output directory, paths are kept relative to the root argument.
+func outputPath(root string, file string, outDir string) (string, error) {
+	if outDir == "" {
+		return file + ".kast", nil
+	}
+	if root == file {
+		return filepath.Join(outDir, filepath.Base(file)+".kast"), nil
+	}
+	relativePath, err := filepath.Rel(root, file)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(outDir, relativePath+".kast"), nil
+}
+
+// checkCollision records the output path of a file.
+// It fails if another file was already converted to the same path, and signals files that were already converted.
+func checkCollision(writtenFrom map[string]string, file string, outPath string) error {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	absOutPath, err := filepath.Abs(outPath)
+	if err != nil {
+		return err
+	}
+	previousFile, written := writtenFrom[absOutPath]
+	if !written {
+		writtenFrom[absOutPath] = absFile
+		return nil
+	}
+	if previousFile == absFile {
+		return errAlreadyConverted
+	}
+	return fmt.Errorf("output %s was already written for %s", outPath, previousFile)
+}
+
+func convertFile(inPath string, outPath string, processor codeProcessor, profile ojkast.KastProfile) error {
+	data, err := os.ReadFile(inPath)
+	if err != nil {
+		return err
+	}
+
+	// the library callback cannot fail, so the first error is kept and checked after the conversion
+	var codeErr error
+	processCode := func(testPath string, value string) string {
+		if processor == nil || codeErr != nil || !strings.HasPrefix(value, filePrefix) {
+			return value
+		}
+		codePath := strings.TrimPrefix(value, filePrefix)
+		if !filepath.IsAbs(codePath) {
+			codePath = filepath.Join(testPath, codePath)
+		}
+		code, err := processor(codePath)
+		if err != nil {
+			codeErr = fmt.Errorf("processing code \"%s\": %w", value, err)
+			return value
+		}
+		return code
+	}
+
+	kast, err := ojkast.ConvertOrderedJSONToKastWithProfile(data, inPath, processCode, profile)
+	if err != nil {
+		return err
+	}
+	if codeErr != nil {
+		return codeErr
+	}
+
+	err = os.MkdirAll(filepath.Dir(outPath), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, []byte(kast+"\n"), 0644)
+}
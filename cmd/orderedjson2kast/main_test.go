@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func runForTest(args ...string) (int, string) {
+	var stderr bytes.Buffer
+	exitCode := run(args, &stderr)
+	return exitCode, stderr.String()
+}
+
+func TestConvertTestdata(t *testing.T) {
+	outDir := t.TempDir()
+	exitCode, stderr := runForTest("-out", outDir, "-artifact-ext", ".hex", filepath.Join("testdata", "ok"))
+	require.Equal(t, exitOK, exitCode, stderr)
+	kast, err := os.ReadFile(filepath.Join(outDir, "adder.scen.json.kast"))
+	require.Nil(t, err)
+	require.Contains(t, string(kast), `#token("\"0x0061\"","String")`)
+
+	// the whole tree also contains a scenario without its artifact, the others still get converted
+	outDir = t.TempDir()
+	exitCode, stderr = runForTest("-out", outDir, "-artifact-ext", ".hex", "testdata")
+	require.Equal(t, exitError, exitCode)
+	require.Contains(t, stderr, "missing.scen.json")
+	require.FileExists(t, filepath.Join(outDir, "ok", "adder.scen.json.kast"))
+	require.NoFileExists(t, filepath.Join(outDir, "fail", "missing.scen.json.kast"))
+}
+
+func TestConvertUsage(t *testing.T) {
+	exitCode, _ := runForTest()
+	require.Equal(t, exitUsage, exitCode)
+	exitCode, _ = runForTest("-profile", "unknown", "testdata")
+	require.Equal(t, exitUsage, exitCode)
+	exitCode, _ = runForTest("-assembler", "a", "-artifact-ext", ".hex", "testdata")
+	require.Equal(t, exitUsage, exitCode)
+	exitCode, _ = runForTest("-assembler", "assemble 'unterminated", "testdata")
+	require.Equal(t, exitUsage, exitCode)
+}
+
+func TestConvertAbsoluteCodePath(t *testing.T) {
+	dir := t.TempDir()
+	codeDir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(codeDir, "code.hex"), []byte("0x01"), 0644))
+	scenario := `{"steps": [{"step": "scDeploy", "tx": {"contractCode": "file:` + filepath.Join(codeDir, "code.iele") + `"}}]}`
+	scenarioPath := filepath.Join(dir, "absolute.scen.json")
+	require.Nil(t, os.WriteFile(scenarioPath, []byte(scenario), 0644))
+
+	exitCode, stderr := runForTest("-artifact-ext", ".hex", scenarioPath)
+	require.Equal(t, exitOK, exitCode, stderr)
+	kast, err := os.ReadFile(scenarioPath + ".kast")
+	require.Nil(t, err)
+	require.Contains(t, string(kast), `#token("\"0x01\"","String")`)
+}
+
+func TestConvertOutputCollision(t *testing.T) {
+	otherRoot := t.TempDir()
+	contents, err := os.ReadFile(filepath.Join("testdata", "ok", "adder.scen.json"))
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(filepath.Join(otherRoot, "adder.scen.json"), contents, 0644))
+
+	outDir := t.TempDir()
+	exitCode, stderr := runForTest("-out", outDir, filepath.Join("testdata", "ok"), otherRoot)
+	require.Equal(t, exitError, exitCode)
+	require.Contains(t, stderr, "already written")
+
+	// the same input given twice is only converted once
+	outDir = t.TempDir()
+	okRoot := filepath.Join("testdata", "ok")
+	exitCode, stderr = runForTest("-out", outDir, okRoot, okRoot)
+	require.Equal(t, exitOK, exitCode, stderr)
+}
+
+func TestSplitCommand(t *testing.T) {
+	args, err := splitCommand(`assemble --name "two words" 'single \ quoted' escaped\ space`)
+	require.Nil(t, err)
+	require.Equal(t, []string{"assemble", "--name", "two words", `single \ quoted`, "escaped space"}, args)
+
+	args, err = splitCommand(`a "" b`)
+	require.Nil(t, err)
+	require.Equal(t, []string{"a", "", "b"}, args)
+
+	_, err = splitCommand(`a "b`)
+	require.NotNil(t, err)
+	_, err = splitCommand("   ")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const filePrefix = "file:"
+
+var errEmptyCode = errors.New("no code produced")
+
+// errAlreadyConverted signals an input given more than once, e.g. both directly and through its directory.
+var errAlreadyConverted = errors.New("already converted")
+
+// codeProcessor yields the code to put in the KAST, for a code file referenced by a test.
+type codeProcessor func(codePath string) (string, error)
+
+// splitCommand splits a command line into arguments at unquoted whitespace.
+// Single quotes keep everything as it is, double quotes and unquoted text allow backslash escapes.
+func splitCommand(commandLine string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, c := range commandLine {
+		switch {
+		case escaped:
+			current.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\\':
+			escaped = true
+			inArg = true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+	return args, nil
+}
+
+// runAssembler runs the command with the code path as last argument. Its standard output becomes the code.
+func runAssembler(command []string, codePath string) (string, error) {
+	args := append(append([]string{}, command[1:]...), codePath)
+	cmd := exec.Command(command[0], args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			return "", fmt.Errorf("%s failed: %w", command[0], err)
+		}
+		return "", fmt.Errorf("%s failed: %w: %s", command[0], err, message)
+	}
+	code := strings.TrimSpace(stdout.String())
+	if code == "" {
+		return "", fmt.Errorf("%s: %w", command[0], errEmptyCode)
+	}
+	return code, nil
+}
+
+// readArtifact reads the code from the file with the same name as the code file, but the artifact extension.
+func readArtifact(codePath string, extension string) (string, error) {
+	artifactPath := strings.TrimSuffix(codePath, filepath.Ext(codePath)) + extension
+	contents, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return "", err
+	}
+	code := strings.TrimSpace(string(contents))
+	if code == "" {
+		return "", fmt.Errorf("%s: %w", artifactPath, errEmptyCode)
+	}
+	return code, nil
+}
@@ -3,8 +3,10 @@ module github.com/kalyan3104/dme-vm-util
 go 1.17
 
 require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.0.1
 	github.com/kalyan3104/dme-components-big-int v0.0.1
 	github.com/kalyan3104/dme-vm-common v0.0.1
+	github.com/kilic/bls12-381 v0.1.0
 	github.com/stretchr/testify v1.8.4
 	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550
 )
@@ -12,6 +14,6 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/sys v0.12.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,120 @@
+package callbackblockchain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// AddressLength is the length of account addresses, in bytes.
+const AddressLength = 32
+
+// VMTypeLength is the length of the VM type, in bytes.
+const VMTypeLength = 2
+
+// scAddressPrefixLength is the number of leading bytes of contract addresses that are not taken from the hash:
+// zeroes, followed by the VM type.
+const scAddressPrefixLength = 10
+
+// shardSuffixLength is the number of trailing address bytes that get copied from the creator, so the contract stays in its shard.
+const shardSuffixLength = 2
+
+// AddressGenerator computes the addresses of newly deployed contracts.
+type AddressGenerator interface {
+	// GenerateAddress yields the address of a contract deployed by the creator, at the given creator nonce, to the given VM.
+	GenerateAddress(creatorAddress []byte, creatorNonce uint64, vmType []byte) ([]byte, error)
+}
+
+// ProtocolAddressGenerator derives contract addresses the same way the protocol does:
+// the keccak256 hash of the creator address and nonce, with zeroes and the VM type in front
+// and the last bytes of the creator address at the end.
+type ProtocolAddressGenerator struct{}
+
+var _ AddressGenerator = (*ProtocolAddressGenerator)(nil)
+
+// GenerateAddress yields the address the protocol would assign to the new contract.
+func (*ProtocolAddressGenerator) GenerateAddress(creatorAddress []byte, creatorNonce uint64, vmType []byte) ([]byte, error) {
+	if len(creatorAddress) != AddressLength {
+		return nil, fmt.Errorf("creator address must be %d bytes long, got %d", AddressLength, len(creatorAddress))
+	}
+	if len(vmType) != VMTypeLength {
+		return nil, fmt.Errorf("VM type must be %d bytes long, got %d", VMTypeLength, len(vmType))
+	}
+
+	nonceBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nonceBytes, creatorNonce)
+	hasher := sha3.NewLegacyKeccak256()
+	_, _ = hasher.Write(creatorAddress)
+	_, _ = hasher.Write(nonceBytes)
+	address := hasher.Sum(nil)
+
+	for i := 0; i < scAddressPrefixLength-VMTypeLength; i++ {
+		address[i] = 0
+	}
+	copy(address[scAddressPrefixLength-VMTypeLength:scAddressPrefixLength], vmType)
+	copy(address[AddressLength-shardSuffixLength:], creatorAddress[AddressLength-shardSuffixLength:])
+	return address, nil
+}
+
+// SimpleAddressGenerator produces addresses that are easy to recognize in tests:
+// the VM type, 0x11 markers, the beginning of the creator address, the nonce
+// and the last bytes of the creator address, so the contract stays in its shard.
+// Nonces above 255 do not fit in the usual layout, those addresses get 0x12 markers and the full 8-byte nonce.
+type SimpleAddressGenerator struct{}
+
+var _ AddressGenerator = (*SimpleAddressGenerator)(nil)
+
+// GenerateAddress yields a readable address for the new contract. It accepts creator addresses of any length.
+func (*SimpleAddressGenerator) GenerateAddress(creatorAddress []byte, creatorNonce uint64, vmType []byte) ([]byte, error) {
+	if len(vmType) > VMTypeLength {
+		return nil, fmt.Errorf("VM type must be at most %d bytes long, got %d", VMTypeLength, len(vmType))
+	}
+
+	result := make([]byte, AddressLength)
+	copy(result[scAddressPrefixLength-len(vmType):scAddressPrefixLength], vmType)
+
+	nonceEnd := AddressLength - shardSuffixLength
+	if creatorNonce <= 0xff {
+		copy(result[10:14], []byte{0x11, 0x11, 0x11, 0x11})
+		copy(result[14:nonceEnd-1], creatorAddress)
+		result[nonceEnd-1] = byte(creatorNonce)
+	} else {
+		copy(result[10:14], []byte{0x12, 0x12, 0x12, 0x12})
+		copy(result[14:nonceEnd-8], creatorAddress)
+		binary.BigEndian.PutUint64(result[nonceEnd-8:nonceEnd], creatorNonce)
+	}
+
+	suffixLength := shardSuffixLength
+	if len(creatorAddress) < suffixLength {
+		suffixLength = len(creatorAddress)
+	}
+	copy(result[AddressLength-suffixLength:], creatorAddress[len(creatorAddress)-suffixLength:])
+	return result, nil
+}
+
+// NewAddressMockList holds explicit new addresses, for given creators and nonces.
+// As an AddressGenerator it is strict: deployments that were not mocked fail.
+type NewAddressMockList []*NewAddressMock
+
+var _ AddressGenerator = (NewAddressMockList)(nil)
+
+// Find yields the mocked address for the creator and nonce, or nil if there is none.
+func (list NewAddressMockList) Find(creatorAddress []byte, creatorNonce uint64) []byte {
+	for _, newAddressMock := range list {
+		if bytes.Equal(creatorAddress, newAddressMock.CreatorAddress) && creatorNonce == newAddressMock.CreatorNonce {
+			return newAddressMock.NewAddress
+		}
+	}
+	return nil
+}
+
+// GenerateAddress yields the mocked address for the creator and nonce, the VM type is ignored.
+func (list NewAddressMockList) GenerateAddress(creatorAddress []byte, creatorNonce uint64, _ []byte) ([]byte, error) {
+	address := list.Find(creatorAddress, creatorNonce)
+	if address == nil {
+		return nil, fmt.Errorf("no new address mocked for creator 0x%x and nonce %d", creatorAddress, creatorNonce)
+	}
+	return address, nil
+}
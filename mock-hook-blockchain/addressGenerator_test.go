@@ -0,0 +1,94 @@
+package callbackblockchain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testVMType = []byte{0x05, 0x00}
+
+func TestProtocolAddressGenerator(t *testing.T) {
+	generator := &ProtocolAddressGenerator{}
+	creator := []byte("creator_address_________________")
+
+	address, err := generator.GenerateAddress(creator, 1, testVMType)
+	require.Nil(t, err)
+	require.Len(t, address, AddressLength)
+	require.Equal(t, make([]byte, 8), address[:8])
+	require.Equal(t, testVMType, address[8:10])
+	require.Equal(t, creator[30:], address[30:])
+
+	sameAddress, err := generator.GenerateAddress(creator, 1, testVMType)
+	require.Nil(t, err)
+	require.Equal(t, address, sameAddress)
+	otherAddress, err := generator.GenerateAddress(creator, 2, testVMType)
+	require.Nil(t, err)
+	require.NotEqual(t, address, otherAddress)
+
+	_, err = generator.GenerateAddress([]byte("short"), 1, testVMType)
+	require.NotNil(t, err)
+	_, err = generator.GenerateAddress(creator, 1, []byte{5})
+	require.NotNil(t, err)
+}
+
+func TestSimpleAddressGenerator(t *testing.T) {
+	generator := &SimpleAddressGenerator{}
+	creator := []byte("creator_address_________________")
+
+	address, err := generator.GenerateAddress(creator, 5, nil)
+	require.Nil(t, err)
+	require.Equal(t, []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x11\x11\x11\x11creator_address\x05__"), address)
+
+	address, err = generator.GenerateAddress(creator, 5, testVMType)
+	require.Nil(t, err)
+	require.Equal(t, testVMType, address[8:10])
+
+	// nonces above 255 are not truncated
+	address256, err := generator.GenerateAddress(creator, 256, nil)
+	require.Nil(t, err)
+	address512, err := generator.GenerateAddress(creator, 512, nil)
+	require.Nil(t, err)
+	require.NotEqual(t, address256, address512)
+	require.Equal(t, []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x12\x12\x12creator_\x00\x00\x00\x00\x00\x00\x01\x00__"), address256)
+
+	// short addresses do not panic
+	address, err = generator.GenerateAddress([]byte("c"), 1, nil)
+	require.Nil(t, err)
+	require.Len(t, address, AddressLength)
+	require.Equal(t, byte('c'), address[31])
+}
+
+func TestNewAddressGeneratorSelection(t *testing.T) {
+	world := NewMock()
+	creator := []byte("creator_address_________________")
+	mockedAddress := []byte("mocked_address__________________")
+	world.NewAddressMocks = append(world.NewAddressMocks, &NewAddressMock{
+		CreatorAddress: creator,
+		CreatorNonce:   1,
+		NewAddress:     mockedAddress,
+	})
+
+	// no generator: the VM decides
+	address, err := world.NewAddress(creator, 2, testVMType)
+	require.Nil(t, err)
+	require.Empty(t, address)
+
+	world.AddressGenerator = &ProtocolAddressGenerator{}
+	address, err = world.NewAddress(creator, 1, testVMType)
+	require.Nil(t, err)
+	require.Equal(t, mockedAddress, address)
+	address, err = world.NewAddress(creator, 2, testVMType)
+	require.Nil(t, err)
+	require.Equal(t, testVMType, address[8:10])
+
+	// only mocked addresses allowed
+	world.AddressGenerator = NewAddressMockList{}
+	_, err = world.NewAddress(creator, 2, testVMType)
+	require.NotNil(t, err)
+
+	world.EnableMockAddressGeneration()
+	address, err = world.NewAddress(creator, 2, testVMType)
+	require.Nil(t, err)
+	require.Equal(t, byte(0x11), address[10])
+}
@@ -0,0 +1,60 @@
+package callbackblockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// GasSchedule holds the gas economics parameters of the mock.
+// The zero value means that transactions only cost the gas consumed by the VM and no developer rewards are paid.
+type GasSchedule struct {
+	// MinGasLimit is the gas cost of a move balance transaction without data.
+	MinGasLimit uint64 `json:"minGasLimit"`
+
+	// GasPerDataByte is the extra gas cost of each byte of transaction data.
+	GasPerDataByte uint64 `json:"gasPerDataByte"`
+
+	// DeveloperFeePercentage is the part of the fee paid for contract execution
+	// that is credited to the developer reward of the contract, between 0 and 100.
+	DeveloperFeePercentage uint64 `json:"developerFeePercentage"`
+}
+
+// LoadGasSchedule reads a gas schedule from a JSON file, for example:
+//
+//	{
+//		"minGasLimit": 50000,
+//		"gasPerDataByte": 1500,
+//		"developerFeePercentage": 30
+//	}
+//
+// Missing fields default to 0, unknown fields are an error.
+func LoadGasSchedule(path string) (*GasSchedule, error) {
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGasSchedule(byteValue)
+}
+
+// ParseGasSchedule reads a gas schedule from its JSON representation.
+func ParseGasSchedule(jsonBytes []byte) (*GasSchedule, error) {
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.DisallowUnknownFields()
+
+	gasSchedule := &GasSchedule{}
+	err := decoder.Decode(gasSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas schedule: %w", err)
+	}
+	if gasSchedule.DeveloperFeePercentage > 100 {
+		return nil, fmt.Errorf("invalid gas schedule: developer fee percentage above 100: %d", gasSchedule.DeveloperFeePercentage)
+	}
+	return gasSchedule, nil
+}
+
+// MoveBalanceGas yields the gas consumed by a transaction before reaching the VM, based on its data length.
+func (gs *GasSchedule) MoveBalanceGas(dataLength int) uint64 {
+	return gs.MinGasLimit + gs.GasPerDataByte*uint64(dataLength)
+}
@@ -0,0 +1,72 @@
+package callbackblockchain
+
+import (
+	"errors"
+	"math"
+)
+
+// ShardCoordinator decides which shard an address belongs to.
+type ShardCoordinator interface {
+	// NumberOfShards yields the total number of shards.
+	NumberOfShards() uint32
+
+	// ComputeShardID yields the shard of an address.
+	ComputeShardID(address []byte) uint32
+}
+
+// MultiShardCoordinator derives shard IDs from the last bytes of the address, the same way the protocol does.
+type MultiShardCoordinator struct {
+	numberOfShards uint32
+	maskHigh       uint32
+	maskLow        uint32
+}
+
+var _ ShardCoordinator = (*MultiShardCoordinator)(nil)
+
+// ErrInvalidNumberOfShards signals that a coordinator was requested for 0 shards.
+var ErrInvalidNumberOfShards = errors.New("number of shards must be at least 1")
+
+// NewMultiShardCoordinator creates a new MultiShardCoordinator instance. The number of shards must be at least 1.
+func NewMultiShardCoordinator(numberOfShards uint32) (*MultiShardCoordinator, error) {
+	if numberOfShards == 0 {
+		return nil, ErrInvalidNumberOfShards
+	}
+	coordinator := &MultiShardCoordinator{
+		numberOfShards: numberOfShards,
+	}
+	if numberOfShards > 1 {
+		n := uint(math.Ceil(math.Log2(float64(numberOfShards))))
+		coordinator.maskHigh = (1 << n) - 1
+		coordinator.maskLow = (1 << (n - 1)) - 1
+	}
+	return coordinator, nil
+}
+
+// NumberOfShards yields the total number of shards.
+func (msc *MultiShardCoordinator) NumberOfShards() uint32 {
+	return msc.numberOfShards
+}
+
+// ComputeShardID yields the shard of an address, based on its last bytes.
+func (msc *MultiShardCoordinator) ComputeShardID(address []byte) uint32 {
+	if msc.numberOfShards == 1 {
+		return 0
+	}
+
+	bytesNeeded := int(msc.numberOfShards/256) + 1
+	startingIndex := 0
+	if len(address) > bytesNeeded {
+		startingIndex = len(address) - bytesNeeded
+	}
+
+	addr := uint32(0)
+	for _, b := range address[startingIndex:] {
+		addr = addr<<8 + uint32(b)
+	}
+
+	shard := addr & msc.maskHigh
+	if shard > msc.numberOfShards-1 {
+		shard = addr & msc.maskLow
+	}
+	return shard
+}
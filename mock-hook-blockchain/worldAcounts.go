@@ -14,6 +14,7 @@ type Account struct {
 	Nonce           uint64
 	Balance         *big.Int
 	BalanceDelta    *big.Int
+	DeveloperReward *big.Int
 	Storage         map[string][]byte
 	Code            []byte
 	CodeMetadata    []byte
@@ -111,9 +112,12 @@ func (a *Account) GetBalance() *big.Int {
 	return a.Balance
 }
 
-// GetDeveloperReward -
+// GetDeveloperReward yields a copy of the accumulated developer reward.
 func (a *Account) GetDeveloperReward() *big.Int {
-	return big.NewInt(0)
+	if a.DeveloperReward == nil {
+		return big.NewInt(0)
+	}
+	return big.NewInt(0).Set(a.DeveloperReward)
 }
 
 // GetOwnerAddress -
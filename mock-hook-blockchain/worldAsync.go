@@ -0,0 +1,148 @@
+package callbackblockchain
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+	"github.com/kalyan3104/dme-vm-common/parsers"
+)
+
+// DefaultCallbackFunction is the function called on the caller contract, once an asynchronous call is finished.
+// It can be overridden per call with BlockchainHookMock.AsyncCallbackFunction.
+const DefaultCallbackFunction = "callBack"
+
+// AsyncCallbackFunc yields the callback function for an asynchronous call, when the call gets recorded.
+type AsyncCallbackFunc func(asyncCall *AsyncCall) string
+
+// AsyncCallResult holds the outcome of a contract call.
+type AsyncCallResult struct {
+	ReturnCode vmi.ReturnCode `json:"returnCode"`
+	ReturnData [][]byte       `json:"returnData"`
+}
+
+// AsyncCall is an asynchronous call made by a contract, as recorded by the mock.
+// It is pending until both the call and the callback were executed.
+type AsyncCall struct {
+	Caller      []byte   `json:"caller"`
+	Destination []byte   `json:"destination"`
+	Function    string   `json:"function"`
+	Arguments   [][]byte `json:"arguments"`
+	Value       *big.Int `json:"value"`
+	GasLimit    uint64   `json:"gasLimit"`
+	Callback    string   `json:"callback"`
+
+	// Result is the outcome of the call on the destination, nil while not yet executed.
+	Result *AsyncCallResult `json:"result,omitempty"`
+
+	// CallbackResult is the outcome of the callback on the caller, nil while not yet executed.
+	CallbackResult *AsyncCallResult `json:"callbackResult,omitempty"`
+}
+
+// IsCompleted is true once the callback was executed.
+func (ac *AsyncCall) IsCompleted() bool {
+	return ac.CallbackResult != nil
+}
+
+// SetResult records the outcome of the call on the destination.
+func (ac *AsyncCall) SetResult(returnCode vmi.ReturnCode, returnData [][]byte) {
+	ac.Result = &AsyncCallResult{
+		ReturnCode: returnCode,
+		ReturnData: returnData,
+	}
+}
+
+// SetCallbackResult records the outcome of the callback, completing the asynchronous call.
+func (ac *AsyncCall) SetCallbackResult(returnCode vmi.ReturnCode, returnData [][]byte) {
+	ac.CallbackResult = &AsyncCallResult{
+		ReturnCode: returnCode,
+		ReturnData: returnData,
+	}
+}
+
+// RecordAsyncCalls saves all asynchronous calls found in the output of a contract execution.
+// The output accounts that represent asynchronous calls are the ones with call type AsynchronousCall and non-empty data.
+// It is called by UpdateWorldStateAfter, executors only need it if they settle the transaction differently.
+// The mock cannot execute the calls, executors should retrieve them with PendingAsyncCalls and process them.
+func (b *BlockchainHookMock) RecordAsyncCalls(contractAddress []byte, modifiedAccounts []*vmi.OutputAccount) ([]*AsyncCall, error) {
+	recorded, err := b.parseAsyncCalls(contractAddress, modifiedAccounts)
+	if err != nil {
+		return nil, err
+	}
+	b.AsyncCalls = append(b.AsyncCalls, recorded...)
+	return recorded, nil
+}
+
+// parseAsyncCalls extracts the asynchronous calls from the output, without recording them.
+func (b *BlockchainHookMock) parseAsyncCalls(contractAddress []byte, modifiedAccounts []*vmi.OutputAccount) ([]*AsyncCall, error) {
+	var result []*AsyncCall
+	for _, modAcct := range modifiedAccounts {
+		if modAcct.CallType != vmi.AsynchronousCall || len(modAcct.Data) == 0 {
+			continue
+		}
+
+		function, arguments, err := parsers.NewCallArgsParser().ParseData(string(modAcct.Data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid async call data: %w", err)
+		}
+		value := big.NewInt(0)
+		if modAcct.BalanceDelta != nil {
+			value.Set(modAcct.BalanceDelta)
+		}
+
+		asyncCall := &AsyncCall{
+			Caller:      contractAddress,
+			Destination: modAcct.Address,
+			Function:    function,
+			Arguments:   arguments,
+			Value:       value,
+			GasLimit:    modAcct.GasLimit,
+		}
+		asyncCall.Callback = b.callbackFunction(asyncCall)
+		result = append(result, asyncCall)
+	}
+	return result, nil
+}
+
+// sortedOutputAccounts orders the output accounts by address, since the VM output does not keep the order of the calls.
+func sortedOutputAccounts(outputAccounts map[string]*vmi.OutputAccount) []*vmi.OutputAccount {
+	result := make([]*vmi.OutputAccount, 0, len(outputAccounts))
+	for _, outputAccount := range outputAccounts {
+		result = append(result, outputAccount)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Address, result[j].Address) < 0
+	})
+	return result
+}
+
+func (b *BlockchainHookMock) callbackFunction(asyncCall *AsyncCall) string {
+	if b.AsyncCallbackFunction == nil {
+		return DefaultCallbackFunction
+	}
+	return b.AsyncCallbackFunction(asyncCall)
+}
+
+// PendingAsyncCalls yields the asynchronous calls that were not completed yet, in the order they were made.
+func (b *BlockchainHookMock) PendingAsyncCalls() []*AsyncCall {
+	var result []*AsyncCall
+	for _, asyncCall := range b.AsyncCalls {
+		if !asyncCall.IsCompleted() {
+			result = append(result, asyncCall)
+		}
+	}
+	return result
+}
+
+// CompletedAsyncCalls yields the asynchronous calls whose callback was executed, in the order they were made.
+func (b *BlockchainHookMock) CompletedAsyncCalls() []*AsyncCall {
+	var result []*AsyncCall
+	for _, asyncCall := range b.AsyncCalls {
+		if asyncCall.IsCompleted() {
+			result = append(result, asyncCall)
+		}
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package callbackblockchain
+
+import (
+	"math/big"
+	"testing"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func parseCheckAsyncCalls(t *testing.T, snippet string) *mj.CheckAsyncCalls {
+	p := mjparse.Parser{}
+	step, err := p.ParseScenarioStep(snippet)
+	require.Nil(t, err)
+	checkStateStep, isCheckState := step.(*mj.CheckStateStep)
+	require.True(t, isCheckState)
+	return checkStateStep.CheckAsyncCalls
+}
+
+func TestRecordAsyncCalls(t *testing.T) {
+	world := NewMock()
+	contract := []byte("contract________________________")
+	destination := []byte("destination_____________________")
+
+	recorded, err := world.RecordAsyncCalls(contract, []*vmi.OutputAccount{
+		{
+			Address:      contract,
+			BalanceDelta: big.NewInt(-10),
+		},
+		{
+			Address:      destination,
+			BalanceDelta: big.NewInt(10),
+			Data:         []byte("transfer@01@0a"),
+			GasLimit:     5000,
+			CallType:     vmi.AsynchronousCall,
+		},
+	})
+	require.Nil(t, err)
+	require.Len(t, recorded, 1)
+	require.Equal(t, recorded, world.PendingAsyncCalls())
+	require.Empty(t, world.CompletedAsyncCalls())
+
+	asyncCall := recorded[0]
+	require.Equal(t, contract, asyncCall.Caller)
+	require.Equal(t, destination, asyncCall.Destination)
+	require.Equal(t, "transfer", asyncCall.Function)
+	require.Equal(t, [][]byte{{1}, {10}}, asyncCall.Arguments)
+	require.Equal(t, big.NewInt(10), asyncCall.Value)
+	require.Equal(t, DefaultCallbackFunction, asyncCall.Callback)
+
+	err = world.CheckAsyncCalls(parseCheckAsyncCalls(t, `{
+		"step": "checkState",
+		"asyncCalls": {
+			"pending": [
+				{
+					"from": "''contract________________________",
+					"to": "''destination_____________________",
+					"function": "transfer",
+					"arguments": [ "1", "10" ],
+					"value": "10",
+					"gasLimit": "5000",
+					"callback": "callBack"
+				}
+			],
+			"completed": []
+		}
+	}`))
+	require.Nil(t, err)
+
+	asyncCall.SetResult(vmi.Ok, [][]byte{{5}})
+	require.False(t, asyncCall.IsCompleted())
+	asyncCall.SetCallbackResult(vmi.Ok, nil)
+	require.True(t, asyncCall.IsCompleted())
+	require.Empty(t, world.PendingAsyncCalls())
+
+	err = world.CheckAsyncCalls(parseCheckAsyncCalls(t, `{
+		"step": "checkState",
+		"asyncCalls": {
+			"pending": [],
+			"completed": [
+				{
+					"function": "transfer",
+					"status": "0",
+					"out": [ "5" ]
+				}
+			]
+		}
+	}`))
+	require.Nil(t, err)
+
+	err = world.CheckAsyncCalls(parseCheckAsyncCalls(t, `{
+		"step": "checkState",
+		"asyncCalls": {
+			"completed": [
+				{
+					"status": "4"
+				}
+			]
+		}
+	}`))
+	require.NotNil(t, err)
+}
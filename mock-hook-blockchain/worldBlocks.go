@@ -0,0 +1,125 @@
+package callbackblockchain
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+)
+
+// DefaultSecondsPerRound is the round duration used when the block config does not specify one.
+const DefaultSecondsPerRound = 6
+
+// BlockConfig configures how the mock produces new blocks.
+type BlockConfig struct {
+	// SecondsPerRound is the timestamp increment for each round. Zero means DefaultSecondsPerRound.
+	SecondsPerRound uint64 `json:"secondsPerRound"`
+
+	// RoundsPerEpoch is used to compute the epoch of new blocks, as round / RoundsPerEpoch.
+	// Zero means that the epoch never changes.
+	RoundsPerEpoch uint64 `json:"roundsPerEpoch"`
+
+	// BlockhashWindow is how many blocks back GetBlockhash can look, relative to the current block.
+	// Zero means no limit, like in the protocol, where all block headers are available from storage.
+	BlockhashWindow uint64 `json:"blockhashWindow"`
+}
+
+func (b *BlockchainHookMock) blockhashWindow() uint64 {
+	if b.BlockConfig == nil {
+		return 0
+	}
+	return b.BlockConfig.BlockhashWindow
+}
+
+// SetBlockhash saves the hash of the block with the given nonce.
+func (b *BlockchainHookMock) SetBlockhash(nonce uint64, hash []byte) {
+	if b.Blockhashes == nil {
+		b.Blockhashes = make(map[uint64][]byte)
+	}
+	b.Blockhashes[nonce] = hash
+}
+
+func (b *BlockchainHookMock) secondsPerRound() uint64 {
+	if b.BlockConfig == nil || b.BlockConfig.SecondsPerRound == 0 {
+		return DefaultSecondsPerRound
+	}
+	return b.BlockConfig.SecondsPerRound
+}
+
+// AdvanceBlock produces n consecutive blocks, one per round.
+func (b *BlockchainHookMock) AdvanceBlock(n int) {
+	for i := 0; i < n; i++ {
+		b.ProduceBlock(1, b.secondsPerRound())
+	}
+}
+
+// ProduceBlock finalizes the current block and starts a new one.
+// The current block becomes the previous block and its generated hash is saved under its nonce,
+// unless a hash was already set for it.
+// The new block has the next nonce, the given number of rounds later and the given number of seconds later.
+// Its epoch is computed from the round, if the block config specifies the rounds per epoch,
+// and its random seed is derived from the previous one.
+func (b *BlockchainHookMock) ProduceBlock(rounds uint64, seconds uint64) {
+	current := BlockInfo{}
+	if b.CurrentBlockInfo != nil {
+		current = *b.CurrentBlockInfo
+	}
+	if _, hashKnown := b.Blockhashes[current.BlockNonce]; !hashKnown {
+		b.SetBlockhash(current.BlockNonce, b.generateBlockHash(&current))
+	}
+
+	previous := current
+	b.PreviousBlockInfo = &previous
+
+	current.BlockNonce++
+	current.BlockRound += rounds
+	current.BlockTimestamp += seconds
+	if b.BlockConfig != nil && b.BlockConfig.RoundsPerEpoch > 0 {
+		current.BlockEpoch = uint32(current.BlockRound / b.BlockConfig.RoundsPerEpoch)
+	}
+	current.RandomSeed = nextRandomSeed(previous.RandomSeed, current.BlockNonce)
+	b.CurrentBlockInfo = &current
+}
+
+// ApplyNextBlockStep produces a new block, as specified in a mandos nextBlock step.
+// Rounds default to 1 and seconds default to the duration of the rounds.
+func (b *BlockchainHookMock) ApplyNextBlockStep(step *mj.NextBlockStep) error {
+	rounds := uint64(1)
+	if len(step.Rounds.Original) > 0 {
+		rounds = step.Rounds.Value
+	}
+	if rounds == 0 {
+		return errors.New("a new block needs at least 1 round")
+	}
+	seconds := rounds * b.secondsPerRound()
+	if len(step.Seconds.Original) > 0 {
+		seconds = step.Seconds.Value
+	}
+	b.ProduceBlock(rounds, seconds)
+	return nil
+}
+
+// generateBlockHash derives a deterministic hash from the block info and the hash of the block before it, if known.
+func (b *BlockchainHookMock) generateBlockHash(blockInfo *BlockInfo) []byte {
+	hasher := sha256.New()
+	if blockInfo.BlockNonce > 0 {
+		_, _ = hasher.Write(b.Blockhashes[blockInfo.BlockNonce-1])
+	}
+	header := make([]byte, 28)
+	binary.BigEndian.PutUint64(header[0:], blockInfo.BlockNonce)
+	binary.BigEndian.PutUint64(header[8:], blockInfo.BlockRound)
+	binary.BigEndian.PutUint64(header[16:], blockInfo.BlockTimestamp)
+	binary.BigEndian.PutUint32(header[24:], blockInfo.BlockEpoch)
+	_, _ = hasher.Write(header)
+	return hasher.Sum(nil)
+}
+
+func nextRandomSeed(previousSeed []byte, nonce uint64) []byte {
+	hasher := sha256.New()
+	_, _ = hasher.Write(previousSeed)
+	nonceBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(nonceBytes, nonce)
+	_, _ = hasher.Write(nonceBytes)
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,145 @@
+package callbackblockchain
+
+import (
+	"testing"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvanceBlock(t *testing.T) {
+	world := NewMock()
+	world.BlockConfig = &BlockConfig{RoundsPerEpoch: 2}
+	world.CurrentBlockInfo = &BlockInfo{BlockNonce: 5, BlockRound: 7, BlockTimestamp: 100}
+
+	world.AdvanceBlock(1)
+	require.Equal(t, uint64(5), world.LastNonce())
+	require.Equal(t, uint64(6), world.CurrentNonce())
+	require.Equal(t, uint64(8), world.CurrentRound())
+	require.Equal(t, uint64(106), world.CurrentTimeStamp())
+	require.Equal(t, uint32(4), world.CurrentEpoch())
+	require.Len(t, world.Blockhashes, 1)
+	require.Len(t, world.CurrentRandomSeed(), 32)
+	require.Nil(t, world.LastRandomSeed())
+
+	seed := world.CurrentRandomSeed()
+	world.AdvanceBlock(2)
+	require.Equal(t, uint64(8), world.CurrentNonce())
+	require.Equal(t, uint64(10), world.CurrentRound())
+	require.Equal(t, uint64(118), world.CurrentTimeStamp())
+	require.Equal(t, uint32(5), world.CurrentEpoch())
+	require.Len(t, world.Blockhashes, 3)
+	require.NotEqual(t, world.Blockhashes[6], world.Blockhashes[7])
+	require.NotEqual(t, seed, world.CurrentRandomSeed())
+
+	// block production is deterministic
+	other := NewMock()
+	other.BlockConfig = &BlockConfig{RoundsPerEpoch: 2}
+	other.CurrentBlockInfo = &BlockInfo{BlockNonce: 5, BlockRound: 7, BlockTimestamp: 100}
+	other.AdvanceBlock(3)
+	require.Equal(t, world.Blockhashes, other.Blockhashes)
+	require.Equal(t, world.CurrentRandomSeed(), other.CurrentRandomSeed())
+}
+
+func TestApplyNextBlockStep(t *testing.T) {
+	p := mjparse.Parser{}
+	step, err := p.ParseScenarioStep(`{
+		"step": "nextBlock",
+		"rounds": "3"
+	}`)
+	require.Nil(t, err)
+
+	world := NewMock()
+	err = world.ApplyNextBlockStep(step.(*mj.NextBlockStep))
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), world.CurrentNonce())
+	require.Equal(t, uint64(3), world.CurrentRound())
+	require.Equal(t, uint64(3*DefaultSecondsPerRound), world.CurrentTimeStamp())
+
+	step, err = p.ParseScenarioStep(`{
+		"step": "nextBlock",
+		"seconds": "1"
+	}`)
+	require.Nil(t, err)
+	err = world.ApplyNextBlockStep(step.(*mj.NextBlockStep))
+	require.Nil(t, err)
+	require.Equal(t, uint64(4), world.CurrentRound())
+	require.Equal(t, uint64(3*DefaultSecondsPerRound+1), world.CurrentTimeStamp())
+
+	step, err = p.ParseScenarioStep(`{
+		"step": "nextBlock",
+		"rounds": "0"
+	}`)
+	require.Nil(t, err)
+	err = world.ApplyNextBlockStep(step.(*mj.NextBlockStep))
+	require.NotNil(t, err)
+}
+
+func TestGetBlockhash(t *testing.T) {
+	p := mjparse.Parser{}
+	step, err := p.ParseScenarioStep(`{
+		"step": "setState",
+		"currentBlockInfo": {
+			"blockNonce": "10"
+		},
+		"blockHashes": {
+			"5": "0x05",
+			"9": "0x09"
+		}
+	}`)
+	require.Nil(t, err)
+
+	world := NewMock()
+	world.BlockConfig = &BlockConfig{BlockhashWindow: 5}
+	err = world.ApplySetStateStep(step.(*mj.SetStateStep))
+	require.Nil(t, err)
+
+	hash, err := world.GetBlockhash(9)
+	require.Nil(t, err)
+	require.Equal(t, []byte{9}, hash)
+	hash, err = world.GetBlockhash(5)
+	require.Nil(t, err)
+	require.Equal(t, []byte{5}, hash)
+
+	// unknown
+	_, err = world.GetBlockhash(7)
+	require.NotNil(t, err)
+	// current and future
+	_, err = world.GetBlockhash(10)
+	require.NotNil(t, err)
+	_, err = world.GetBlockhash(11)
+	require.NotNil(t, err)
+
+	// block 5 gets out of the window
+	world.AdvanceBlock(1)
+	_, err = world.GetBlockhash(5)
+	require.NotNil(t, err)
+	hash, err = world.GetBlockhash(10)
+	require.Nil(t, err)
+	require.Len(t, hash, 32)
+}
+
+func TestGetBlockhashWithoutCurrentBlock(t *testing.T) {
+	p := mjparse.Parser{}
+	step, err := p.ParseScenarioStep(`{
+		"step": "setState",
+		"blockHashes": [ "0x00", "0x01" ]
+	}`)
+	require.Nil(t, err)
+
+	world := NewMock()
+	err = world.ApplySetStateStep(step.(*mj.SetStateStep))
+	require.Nil(t, err)
+
+	hash, err := world.GetBlockhash(0)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0}, hash)
+	hash, err = world.GetBlockhash(1)
+	require.Nil(t, err)
+	require.Equal(t, []byte{1}, hash)
+	_, err = world.GetBlockhash(2)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "block 2")
+	require.Contains(t, err.Error(), "only 2 known blockhashes")
+}
@@ -1,8 +1,8 @@
 package callbackblockchain
 
 import (
-	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 
 	vmcommon "github.com/kalyan3104/dme-vm-common"
@@ -12,30 +12,19 @@ var _ vmcommon.BlockchainHook = (*BlockchainHookMock)(nil)
 
 var zero = big.NewInt(0)
 
-// NewAddress adapts between K model and kalyan function
-func (b *BlockchainHookMock) NewAddress(creatorAddress []byte, creatorNonce uint64, _ []byte) ([]byte, error) {
-	// explicit new address mocks
-	for _, newAddressMock := range b.NewAddressMocks {
-		if bytes.Equal(creatorAddress, newAddressMock.CreatorAddress) && creatorNonce == newAddressMock.CreatorNonce {
-			return newAddressMock.NewAddress, nil
-		}
+// NewAddress yields the address of a new contract.
+// Explicit new address mocks take precedence, then the configured address generator is used.
+// Without an address generator, it returns an empty address, which signals the VM to use its own algorithm.
+func (b *BlockchainHookMock) NewAddress(creatorAddress []byte, creatorNonce uint64, vmType []byte) ([]byte, error) {
+	mockedAddress := b.NewAddressMocks.Find(creatorAddress, creatorNonce)
+	if mockedAddress != nil {
+		return mockedAddress, nil
 	}
 
-	// a simple mock algorithm
-	if b.mockAddressGenerationEnabled {
-		result := make([]byte, 32)
-		result[10] = 0x11
-		result[11] = 0x11
-		result[12] = 0x11
-		result[13] = 0x11
-		copy(result[14:29], creatorAddress)
-
-		result[29] = byte(creatorNonce)
-
-		copy(result[30:], creatorAddress[30:])
-
-		return result, nil
+	if b.AddressGenerator != nil {
+		return b.AddressGenerator.GenerateAddress(creatorAddress, creatorNonce, vmType)
 	}
+
 	// empty byte array signals not implemented, fallback to default
 	return []byte{}, nil
 }
@@ -50,14 +39,32 @@ func (b *BlockchainHookMock) GetStorageData(accountAddress []byte, index []byte)
 	return acct.StorageValue(string(index)), nil
 }
 
-// GetBlockhash should return the hash of the nth previous blockchain.
-// Offset specifies how many blocks we need to look back.
+// GetBlockhash returns the hash of the block with the given nonce.
+// Only blocks before the current one are available, and only as far back as the block config allows.
+// Without a current block, as in older tests that only list the block hashes, any known hash is available,
+// the nonce being the index in the list.
 func (b *BlockchainHookMock) GetBlockhash(nonce uint64) ([]byte, error) {
-	offsetInt32 := int(nonce)
-	if offsetInt32 >= len(b.Blockhashes) {
-		return nil, errors.New("blockhash offset exceeds the blockhashes slice")
+	if b.CurrentBlockInfo == nil {
+		hash, found := b.Blockhashes[nonce]
+		if !found {
+			return nil, fmt.Errorf("no blockhash known for block %d, there is no current block and only %d known blockhashes", nonce, len(b.Blockhashes))
+		}
+		return hash, nil
+	}
+
+	currentNonce := b.CurrentNonce()
+	if nonce >= currentNonce {
+		return nil, fmt.Errorf("blockhash requested for block %d, which is not before the current block %d", nonce, currentNonce)
+	}
+	window := b.blockhashWindow()
+	if window > 0 && currentNonce-nonce > window {
+		return nil, fmt.Errorf("blockhash requested for block %d, which is more than %d blocks before the current block %d", nonce, window, currentNonce)
 	}
-	return b.Blockhashes[offsetInt32], nil
+	hash, found := b.Blockhashes[nonce]
+	if !found {
+		return nil, fmt.Errorf("no blockhash known for block %d", nonce)
+	}
+	return hash, nil
 }
 
 // LastNonce returns the nonce from from the last committed block
@@ -86,7 +93,10 @@ func (b *BlockchainHookMock) LastTimeStamp() uint64 {
 
 // LastRandomSeed returns the random seed from the last committed block
 func (b *BlockchainHookMock) LastRandomSeed() []byte {
-	return nil
+	if b.PreviousBlockInfo == nil {
+		return nil
+	}
+	return b.PreviousBlockInfo.RandomSeed
 }
 
 // LastEpoch returns the epoch from the last committed block
@@ -128,7 +138,10 @@ func (b *BlockchainHookMock) CurrentTimeStamp() uint64 {
 
 // CurrentRandomSeed returns the random seed from the current header
 func (b *BlockchainHookMock) CurrentRandomSeed() []byte {
-	return nil
+	if b.CurrentBlockInfo == nil {
+		return nil
+	}
+	return b.CurrentBlockInfo.RandomSeed
 }
 
 // CurrentEpoch returns the current epoch
@@ -164,8 +177,13 @@ func (b *BlockchainHookMock) GetUserAccount(address []byte) (vmcommon.UserAccoun
 	return account, nil
 }
 
-// GetShardOfAddress -
+// GetShardOfAddress yields the shard from the shard coordinator, if there is one,
+// otherwise the shard ID of the account, if it exists.
 func (b *BlockchainHookMock) GetShardOfAddress(address []byte) uint32 {
+	if b.ShardCoordinator != nil {
+		return b.ShardCoordinator.ComputeShardID(address)
+	}
+
 	account := b.AcctMap.GetAccount(address)
 	if account == nil {
 		return 0
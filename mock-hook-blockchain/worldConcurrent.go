@@ -0,0 +1,223 @@
+package callbackblockchain
+
+import (
+	"math/big"
+	"sync"
+
+	vmcommon "github.com/kalyan3104/dme-vm-common"
+)
+
+// ConcurrentBlockchainHookMock wraps a BlockchainHookMock, making it safe for concurrent use,
+// for instance by executors that run several view function queries in parallel against the same world.
+//
+// All BlockchainHook methods are reads and can run in parallel.
+// Writes are exclusive: reads started during a write wait for it to finish,
+// so they observe the world either entirely before or entirely after each write.
+// Several updates can be grouped in a single atomic write with Write.
+// Accounts returned by GetUserAccount are snapshots, later writes do not change them.
+//
+// The wrapped mock should not be accessed directly while the wrapper is in use.
+type ConcurrentBlockchainHookMock struct {
+	mutex sync.RWMutex
+	world *BlockchainHookMock
+}
+
+var _ vmcommon.BlockchainHook = (*ConcurrentBlockchainHookMock)(nil)
+
+// NewConcurrentMock wraps a mock, making it safe for concurrent use.
+func NewConcurrentMock(world *BlockchainHookMock) *ConcurrentBlockchainHookMock {
+	return &ConcurrentBlockchainHookMock{
+		world: world,
+	}
+}
+
+// Read runs a function that inspects the world, in parallel with other reads, but not with writes.
+// The function must not modify the world.
+func (c *ConcurrentBlockchainHookMock) Read(readFunc func(world *BlockchainHookMock)) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	readFunc(c.world)
+}
+
+// Write runs a function that modifies the world, as one atomic transaction: no reads or other writes run in parallel.
+func (c *ConcurrentBlockchainHookMock) Write(writeFunc func(world *BlockchainHookMock) error) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return writeFunc(c.world)
+}
+
+// UpdateAccounts applies the output of a VM to the world, atomically.
+func (c *ConcurrentBlockchainHookMock) UpdateAccounts(
+	modifiedAccounts []*vmcommon.OutputAccount,
+	accountsToDelete [][]byte,
+	callerAddress []byte) error {
+
+	return c.Write(func(world *BlockchainHookMock) error {
+		return world.UpdateAccounts(modifiedAccounts, accountsToDelete, callerAddress)
+	})
+}
+
+// NewAddress -
+func (c *ConcurrentBlockchainHookMock) NewAddress(creatorAddress []byte, creatorNonce uint64, vmType []byte) ([]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.NewAddress(creatorAddress, creatorNonce, vmType)
+}
+
+// GetStorageData -
+func (c *ConcurrentBlockchainHookMock) GetStorageData(accountAddress []byte, index []byte) ([]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.GetStorageData(accountAddress, index)
+}
+
+// GetBlockhash -
+func (c *ConcurrentBlockchainHookMock) GetBlockhash(nonce uint64) ([]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.GetBlockhash(nonce)
+}
+
+// LastNonce -
+func (c *ConcurrentBlockchainHookMock) LastNonce() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.LastNonce()
+}
+
+// LastRound -
+func (c *ConcurrentBlockchainHookMock) LastRound() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.LastRound()
+}
+
+// LastTimeStamp -
+func (c *ConcurrentBlockchainHookMock) LastTimeStamp() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.LastTimeStamp()
+}
+
+// LastRandomSeed -
+func (c *ConcurrentBlockchainHookMock) LastRandomSeed() []byte {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.LastRandomSeed()
+}
+
+// LastEpoch -
+func (c *ConcurrentBlockchainHookMock) LastEpoch() uint32 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.LastEpoch()
+}
+
+// GetStateRootHash -
+func (c *ConcurrentBlockchainHookMock) GetStateRootHash() []byte {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.GetStateRootHash()
+}
+
+// CurrentNonce -
+func (c *ConcurrentBlockchainHookMock) CurrentNonce() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.CurrentNonce()
+}
+
+// CurrentRound -
+func (c *ConcurrentBlockchainHookMock) CurrentRound() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.CurrentRound()
+}
+
+// CurrentTimeStamp -
+func (c *ConcurrentBlockchainHookMock) CurrentTimeStamp() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.CurrentTimeStamp()
+}
+
+// CurrentRandomSeed -
+func (c *ConcurrentBlockchainHookMock) CurrentRandomSeed() []byte {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.CurrentRandomSeed()
+}
+
+// CurrentEpoch -
+func (c *ConcurrentBlockchainHookMock) CurrentEpoch() uint32 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.CurrentEpoch()
+}
+
+// ProcessBuiltInFunction -
+func (c *ConcurrentBlockchainHookMock) ProcessBuiltInFunction(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.ProcessBuiltInFunction(input)
+}
+
+// GetBuiltinFunctionNames -
+func (c *ConcurrentBlockchainHookMock) GetBuiltinFunctionNames() vmcommon.FunctionNames {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.GetBuiltinFunctionNames()
+}
+
+// GetAllState -
+func (c *ConcurrentBlockchainHookMock) GetAllState(address []byte) (map[string][]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.GetAllState(address)
+}
+
+// GetUserAccount yields a snapshot of the account, that is not affected by later writes.
+func (c *ConcurrentBlockchainHookMock) GetUserAccount(address []byte) (vmcommon.UserAccountHandler, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	_, err := c.world.GetUserAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	return c.world.AcctMap.GetAccount(address).Clone(), nil
+}
+
+// GetShardOfAddress -
+func (c *ConcurrentBlockchainHookMock) GetShardOfAddress(address []byte) uint32 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.GetShardOfAddress(address)
+}
+
+// IsSmartContract -
+func (c *ConcurrentBlockchainHookMock) IsSmartContract(address []byte) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.world.IsSmartContract(address)
+}
+
+// Clone yields a copy of the account that can be modified independently.
+// Byte slices are shared, since the mock always replaces them instead of modifying them.
+func (a *Account) Clone() *Account {
+	clone := *a
+	clone.Balance = cloneBigInt(a.Balance)
+	clone.BalanceDelta = cloneBigInt(a.BalanceDelta)
+	clone.DeveloperReward = cloneBigInt(a.DeveloperReward)
+	clone.Storage = make(map[string][]byte, len(a.Storage))
+	for key, value := range a.Storage {
+		clone.Storage[key] = value
+	}
+	return &clone
+}
+
+func cloneBigInt(value *big.Int) *big.Int {
+	if value == nil {
+		return nil
+	}
+	return big.NewInt(0).Set(value)
+}
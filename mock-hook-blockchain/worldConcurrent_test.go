@@ -0,0 +1,104 @@
+package callbackblockchain
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// meant to be run with the race detector: go test -race
+// assert instead of require in goroutines, since FailNow must be called from the test goroutine
+func TestConcurrentMockReadsDuringWrites(t *testing.T) {
+	contract := []byte("contract________________________")
+	caller := []byte("caller__________________________")
+	world := NewMock()
+	world.AcctMap.PutAccount(&Account{
+		Exists:  true,
+		Address: contract,
+		Balance: big.NewInt(0),
+		Storage: map[string][]byte{"counter": {0}},
+	})
+	concurrentWorld := NewConcurrentMock(world)
+
+	nrWrites := 100
+	nrReaders := 4
+	var wg sync.WaitGroup
+	wg.Add(1 + nrReaders)
+
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= nrWrites; i++ {
+			err := concurrentWorld.UpdateAccounts(
+				[]*vmi.OutputAccount{
+					{
+						Address:      contract,
+						BalanceDelta: big.NewInt(1),
+						StorageUpdates: map[string]*vmi.StorageUpdate{
+							"counter": {Offset: []byte("counter"), Data: []byte{byte(i)}},
+						},
+					},
+				},
+				nil,
+				caller)
+			assert.Nil(t, err)
+		}
+	}()
+
+	for r := 0; r < nrReaders; r++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < nrWrites; i++ {
+				counter, err := concurrentWorld.GetStorageData(contract, []byte("counter"))
+				assert.Nil(t, err)
+				assert.Len(t, counter, 1)
+
+				account, err := concurrentWorld.GetUserAccount(contract)
+				assert.Nil(t, err)
+				// balance and storage are updated in the same write, a snapshot sees both or neither
+				snapshot := account.(*Account)
+				assert.Equal(t, int64(snapshot.StorageValue("counter")[0]), snapshot.GetBalance().Int64())
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	account, err := concurrentWorld.GetUserAccount(contract)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(int64(nrWrites)), account.GetBalance())
+}
+
+func TestConcurrentMockSnapshot(t *testing.T) {
+	contract := []byte("contract________________________")
+	world := NewMock()
+	world.AcctMap.PutAccount(&Account{
+		Exists:  true,
+		Address: contract,
+		Balance: big.NewInt(10),
+		Storage: map[string][]byte{"key": []byte("before")},
+	})
+	concurrentWorld := NewConcurrentMock(world)
+
+	account, err := concurrentWorld.GetUserAccount(contract)
+	require.Nil(t, err)
+
+	err = concurrentWorld.Write(func(world *BlockchainHookMock) error {
+		world.AcctMap.GetAccount(contract).Storage["key"] = []byte("after")
+		return world.UpdateBalanceWithDelta(contract, big.NewInt(5))
+	})
+	require.Nil(t, err)
+
+	require.Equal(t, big.NewInt(10), account.GetBalance())
+	require.Equal(t, []byte("before"), account.(*Account).StorageValue("key"))
+
+	concurrentWorld.Read(func(world *BlockchainHookMock) {
+		require.Equal(t, big.NewInt(15), world.AcctMap.GetAccount(contract).Balance)
+	})
+
+	_, err = concurrentWorld.GetUserAccount([]byte("missing_________________________"))
+	require.NotNil(t, err)
+}
@@ -2,47 +2,66 @@ package callbackblockchain
 
 // NewAddressMock allows tests to specify what new addresses to generate
 type NewAddressMock struct {
-	CreatorAddress []byte
-	CreatorNonce   uint64
-	NewAddress     []byte
+	CreatorAddress []byte `json:"creatorAddress"`
+	CreatorNonce   uint64 `json:"creatorNonce"`
+	NewAddress     []byte `json:"newAddress"`
 }
 
 // BlockInfo contains mock data about the corent block
 type BlockInfo struct {
-	BlockTimestamp uint64
-	BlockNonce     uint64
-	BlockRound     uint64
-	BlockEpoch     uint32
+	BlockTimestamp uint64 `json:"blockTimestamp"`
+	BlockNonce     uint64 `json:"blockNonce"`
+	BlockRound     uint64 `json:"blockRound"`
+	BlockEpoch     uint32 `json:"blockEpoch"`
+	RandomSeed     []byte `json:"randomSeed"`
 }
 
 // BlockchainHookMock provides a mock representation of the blockchain to be used in VM tests.
 type BlockchainHookMock struct {
-	AcctMap                      AccountMap
-	PreviousBlockInfo            *BlockInfo
-	CurrentBlockInfo             *BlockInfo
-	Blockhashes                  [][]byte
-	mockAddressGenerationEnabled bool
-	NewAddressMocks              []*NewAddressMock
+	AcctMap           AccountMap
+	PreviousBlockInfo *BlockInfo
+	CurrentBlockInfo  *BlockInfo
+	Blockhashes       map[uint64][]byte
+	NewAddressMocks   NewAddressMockList
+	AsyncCalls        []*AsyncCall
+
+	// AddressGenerator is optional. It computes new contract addresses that are not in NewAddressMocks.
+	// If not set, the VM falls back to its own address generation.
+	AddressGenerator AddressGenerator
+
+	// ShardCoordinator is optional. If set, shards are computed from addresses,
+	// otherwise they are read from the ShardID field of the accounts.
+	ShardCoordinator ShardCoordinator
+
+	// GasSchedule is optional. If not set, transactions only cost the gas consumed by the VM.
+	GasSchedule *GasSchedule
+
+	// AsyncCallbackFunction is optional. It chooses the callback of each recorded asynchronous call,
+	// if not set it is always DefaultCallbackFunction.
+	AsyncCallbackFunction AsyncCallbackFunc
+
+	// BlockConfig is optional. It configures the blocks produced by AdvanceBlock and ProduceBlock.
+	BlockConfig *BlockConfig
 }
 
 // NewMock creates a new mock instance
 func NewMock() *BlockchainHookMock {
 	return &BlockchainHookMock{
-		AcctMap:                      NewAccountMap(),
-		PreviousBlockInfo:            nil,
-		CurrentBlockInfo:             nil,
-		Blockhashes:                  nil,
-		mockAddressGenerationEnabled: false,
+		AcctMap:           NewAccountMap(),
+		PreviousBlockInfo: nil,
+		CurrentBlockInfo:  nil,
+		Blockhashes:       make(map[uint64][]byte),
 	}
 }
 
 // Clear resets all mock data between tests.
 func (b *BlockchainHookMock) Clear() {
 	b.AcctMap = NewAccountMap()
-	b.Blockhashes = nil
+	b.Blockhashes = make(map[uint64][]byte)
+	b.AsyncCalls = nil
 }
 
-// EnableMockAddressGeneration causes the mock to generate its own new addresses.
+// EnableMockAddressGeneration causes the mock to generate its own new addresses, using the SimpleAddressGenerator.
 func (b *BlockchainHookMock) EnableMockAddressGeneration() {
-	b.mockAddressGenerationEnabled = true
+	b.AddressGenerator = &SimpleAddressGenerator{}
 }
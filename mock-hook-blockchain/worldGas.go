@@ -0,0 +1,154 @@
+package callbackblockchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+)
+
+// SetGasSchedule configures the gas economics of the mock. A nil schedule means no move balance costs and no developer rewards.
+func (b *BlockchainHookMock) SetGasSchedule(gasSchedule *GasSchedule) {
+	b.GasSchedule = gasSchedule
+}
+
+func (b *BlockchainHookMock) gasSchedule() *GasSchedule {
+	if b.GasSchedule == nil {
+		return &GasSchedule{}
+	}
+	return b.GasSchedule
+}
+
+// CheckGasLimit verifies that the gas limit of a transaction covers at least its move balance cost.
+func (b *BlockchainHookMock) CheckGasLimit(txData []byte, gasLimit uint64) error {
+	moveBalanceGas := b.gasSchedule().MoveBalanceGas(len(txData))
+	if gasLimit < moveBalanceGas {
+		return fmt.Errorf("insufficient gas limit. Want at least: %d. Have: %d", moveBalanceGas, gasLimit)
+	}
+	return nil
+}
+
+// UpdateWorldStateAfterMoveBalance settles the gas of a transaction that did not reach the VM.
+// It should be called after UpdateWorldStateBefore, it refunds everything above the move balance cost.
+func (b *BlockchainHookMock) UpdateWorldStateAfterMoveBalance(
+	fromAddr []byte,
+	txData []byte,
+	gasLimit uint64,
+	gasPrice uint64) error {
+
+	err := b.CheckGasLimit(txData, gasLimit)
+	if err != nil {
+		return err
+	}
+	sender, err := b.gasSender(fromAddr)
+	if err != nil {
+		return err
+	}
+	gasRemaining := gasLimit - b.gasSchedule().MoveBalanceGas(len(txData))
+	sender.Balance = big.NewInt(0).Add(sender.Balance, gasValue(gasRemaining, gasPrice))
+	return nil
+}
+
+// UpdateWorldStateAfter settles the gas of a transaction executed by the VM.
+// It should be called after UpdateWorldStateBefore and UpdateAccounts:
+// it refunds the gas remaining in the VM output, plus the gas refunded by the VM, at the gas price, to the sender
+// and credits the developer fee percentage of the execution fee to the developer reward of the contract.
+// The move balance cost of the transaction is not subject to developer rewards.
+// It also records the asynchronous calls made by the contract, see RecordAsyncCalls.
+// Nothing changes if it returns an error.
+func (b *BlockchainHookMock) UpdateWorldStateAfter(
+	fromAddr []byte,
+	contractAddr []byte,
+	txData []byte,
+	gasLimit uint64,
+	gasPrice uint64,
+	vmOutput *vmi.VMOutput) error {
+
+	gasRemaining := uint64(0)
+	refundedGas := big.NewInt(0)
+	var asyncCalls []*AsyncCall
+	if vmOutput != nil {
+		gasRemaining = vmOutput.GasRemaining
+		if vmOutput.GasRefund != nil {
+			refundedGas.Set(vmOutput.GasRefund)
+		}
+		var err error
+		asyncCalls, err = b.parseAsyncCalls(contractAddr, sortedOutputAccounts(vmOutput.OutputAccounts))
+		if err != nil {
+			return err
+		}
+	}
+	if gasRemaining > gasLimit {
+		return fmt.Errorf("gas remaining exceeds gas limit. Gas limit: %d. Gas remaining: %d", gasLimit, gasRemaining)
+	}
+	refundedGas.Add(refundedGas, big.NewInt(0).SetUint64(gasRemaining))
+	refund := big.NewInt(0).Mul(refundedGas, big.NewInt(0).SetUint64(gasPrice))
+	developerReward := b.developerReward(txData, gasLimit-gasRemaining, gasPrice)
+
+	sender, err := b.gasSender(fromAddr)
+	if err != nil {
+		return err
+	}
+	var contract *Account
+	if developerReward.Sign() > 0 {
+		contract = b.AcctMap.GetAccount(contractAddr)
+		if contract == nil {
+			return errors.New("method UpdateWorldStateAfter expects an existing contract address")
+		}
+	}
+
+	sender.Balance = big.NewInt(0).Add(sender.Balance, refund)
+	if contract != nil {
+		contract.DeveloperReward = big.NewInt(0).Add(contract.GetDeveloperReward(), developerReward)
+	}
+	b.AsyncCalls = append(b.AsyncCalls, asyncCalls...)
+	return nil
+}
+
+// developerReward yields the developer fee percentage of the execution fee, i.e. the fee above the move balance cost.
+func (b *BlockchainHookMock) developerReward(txData []byte, gasUsed uint64, gasPrice uint64) *big.Int {
+	moveBalanceGas := b.gasSchedule().MoveBalanceGas(len(txData))
+	if gasUsed <= moveBalanceGas {
+		return big.NewInt(0)
+	}
+	developerReward := gasValue(gasUsed-moveBalanceGas, gasPrice)
+	developerReward.Mul(developerReward, big.NewInt(0).SetUint64(b.gasSchedule().DeveloperFeePercentage))
+	return developerReward.Div(developerReward, big.NewInt(100))
+}
+
+// ClaimDeveloperRewards moves the accumulated developer reward of a contract to the balance of its owner.
+// Only the owner can claim. Returns the claimed value.
+func (b *BlockchainHookMock) ClaimDeveloperRewards(contractAddr []byte, callerAddr []byte) (*big.Int, error) {
+	contract := b.AcctMap.GetAccount(contractAddr)
+	if contract == nil {
+		return nil, errors.New("method ClaimDeveloperRewards expects an existing contract address")
+	}
+	if !bytes.Equal(contract.OwnerAddress, callerAddr) {
+		return nil, errors.New("only the contract owner can claim developer rewards")
+	}
+
+	reward := contract.GetDeveloperReward()
+	err := b.UpdateBalanceWithDelta(callerAddr, reward)
+	if err != nil {
+		return nil, err
+	}
+	contract.DeveloperReward = big.NewInt(0)
+	return reward, nil
+}
+
+// gasSender yields the account that gets the gas refund.
+func (b *BlockchainHookMock) gasSender(fromAddr []byte) (*Account, error) {
+	sender := b.AcctMap.GetAccount(fromAddr)
+	if sender == nil {
+		return nil, errors.New("gas refund failed: sender does not exist")
+	}
+	return sender, nil
+}
+
+func gasValue(gas uint64, gasPrice uint64) *big.Int {
+	return big.NewInt(0).Mul(
+		big.NewInt(0).SetUint64(gas),
+		big.NewInt(0).SetUint64(gasPrice))
+}
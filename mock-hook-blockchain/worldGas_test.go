@@ -0,0 +1,124 @@
+package callbackblockchain
+
+import (
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGasSchedule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gasSchedule.json")
+	err := ioutil.WriteFile(path, []byte(`{
+		"minGasLimit": 50000,
+		"gasPerDataByte": 1500,
+		"developerFeePercentage": 30
+	}`), 0644)
+	require.Nil(t, err)
+
+	gasSchedule, err := LoadGasSchedule(path)
+	require.Nil(t, err)
+	require.Equal(t, &GasSchedule{
+		MinGasLimit:            50000,
+		GasPerDataByte:         1500,
+		DeveloperFeePercentage: 30,
+	}, gasSchedule)
+	require.Equal(t, uint64(50000+3*1500), gasSchedule.MoveBalanceGas(3))
+
+	_, err = ParseGasSchedule([]byte(`{"minGasLimit": 1, "unknown": 2}`))
+	require.NotNil(t, err)
+	_, err = ParseGasSchedule([]byte(`{"developerFeePercentage": 101}`))
+	require.NotNil(t, err)
+}
+
+func TestGasMoveBalance(t *testing.T) {
+	world := NewMock()
+	world.SetGasSchedule(&GasSchedule{MinGasLimit: 100, GasPerDataByte: 10})
+	sender := []byte("sender__________________________")
+	world.AcctMap.PutAccount(&Account{Address: sender, Balance: big.NewInt(10000), Storage: make(map[string][]byte)})
+
+	txData := []byte("data")
+	require.NotNil(t, world.CheckGasLimit(txData, 139))
+	require.Nil(t, world.CheckGasLimit(txData, 140))
+
+	err := world.UpdateWorldStateBefore(sender, 1000, 2)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(8000), world.AcctMap.GetAccount(sender).Balance)
+
+	err = world.UpdateWorldStateAfterMoveBalance(sender, txData, 1000, 2)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(10000-140*2), world.AcctMap.GetAccount(sender).Balance)
+	require.Equal(t, uint64(1), world.AcctMap.GetAccount(sender).Nonce)
+}
+
+func TestGasDeveloperRewards(t *testing.T) {
+	world := NewMock()
+	world.SetGasSchedule(&GasSchedule{MinGasLimit: 100, GasPerDataByte: 10, DeveloperFeePercentage: 30})
+	owner := []byte("owner___________________________")
+	sender := []byte("sender__________________________")
+	contract := []byte("contract________________________")
+	world.AcctMap.PutAccount(&Account{Address: owner, Balance: big.NewInt(0), Storage: make(map[string][]byte)})
+	world.AcctMap.PutAccount(&Account{Address: sender, Balance: big.NewInt(10000), Storage: make(map[string][]byte)})
+	world.AcctMap.PutAccount(&Account{
+		Address:         contract,
+		Balance:         big.NewInt(0),
+		Storage:         make(map[string][]byte),
+		Code:            []byte("code"),
+		OwnerAddress:    owner,
+		IsSmartContract: true,
+	})
+	require.Equal(t, big.NewInt(0), world.AcctMap.GetAccount(contract).GetDeveloperReward())
+
+	txData := []byte("call")
+	err := world.UpdateWorldStateBefore(sender, 1000, 1)
+	require.Nil(t, err)
+	err = world.UpdateWorldStateAfter(sender, contract, txData, 1000, 1, &vmi.VMOutput{GasRemaining: 360})
+	require.Nil(t, err)
+
+	// used 640, of which 140 for move balance and 500 for execution
+	require.Equal(t, big.NewInt(10000-640), world.AcctMap.GetAccount(sender).Balance)
+	require.Equal(t, big.NewInt(150), world.AcctMap.GetAccount(contract).GetDeveloperReward())
+
+	err = world.UpdateWorldStateAfter(sender, contract, txData, 1000, 1, &vmi.VMOutput{GasRemaining: 1001})
+	require.NotNil(t, err)
+
+	_, err = world.ClaimDeveloperRewards(contract, sender)
+	require.NotNil(t, err)
+	claimed, err := world.ClaimDeveloperRewards(contract, owner)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(150), claimed)
+	require.Equal(t, big.NewInt(150), world.AcctMap.GetAccount(owner).Balance)
+	require.Equal(t, big.NewInt(0), world.AcctMap.GetAccount(contract).GetDeveloperReward())
+
+	// the reward is a copy, changing it does not change the account
+	world.AcctMap.GetAccount(contract).GetDeveloperReward().SetInt64(10)
+	require.Equal(t, big.NewInt(0), world.AcctMap.GetAccount(contract).GetDeveloperReward())
+}
+
+func TestGasRefundAndMissingContract(t *testing.T) {
+	world := NewMock()
+	world.SetGasSchedule(&GasSchedule{MinGasLimit: 100, DeveloperFeePercentage: 30})
+	sender := []byte("sender__________________________")
+	contract := []byte("contract________________________")
+	world.AcctMap.PutAccount(&Account{Address: sender, Balance: big.NewInt(100000), Storage: make(map[string][]byte)})
+
+	err := world.UpdateWorldStateBefore(sender, 1000, 10)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(90000), world.AcctMap.GetAccount(sender).Balance)
+	err = world.UpdateWorldStateAfter(sender, contract, nil, 1000, 10, &vmi.VMOutput{GasRemaining: 400})
+	require.NotNil(t, err)
+	require.Equal(t, big.NewInt(90000), world.AcctMap.GetAccount(sender).Balance)
+
+	world.AcctMap.PutAccount(&Account{Address: contract, Balance: big.NewInt(0), Storage: make(map[string][]byte)})
+	err = world.UpdateWorldStateAfter(sender, contract, nil, 1000, 10, &vmi.VMOutput{
+		GasRemaining: 400,
+		GasRefund:    big.NewInt(50),
+	})
+	require.Nil(t, err)
+	// the refunded gas is priced like the remaining gas
+	require.Equal(t, big.NewInt(90000+(400+50)*10), world.AcctMap.GetAccount(sender).Balance)
+	require.Equal(t, big.NewInt(1500), world.AcctMap.GetAccount(contract).GetDeveloperReward())
+}
@@ -0,0 +1,354 @@
+package callbackblockchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+)
+
+// ApplySetStateStep loads all data from a mandos setState step into the mock:
+// accounts, block infos, block hashes and new address mocks.
+// Accounts replace existing accounts with the same address,
+// block infos and block hashes are only replaced if present in the step,
+// new address mocks are added to the existing ones.
+func (b *BlockchainHookMock) ApplySetStateStep(step *mj.SetStateStep) error {
+	accounts := make([]*Account, 0, len(step.Accounts))
+	for _, mandosAccount := range step.Accounts {
+		account, err := AccountFromMandos(mandosAccount)
+		if err != nil {
+			return fmt.Errorf("invalid account %s: %w", mandosAccount.Address.Original, err)
+		}
+		accounts = append(accounts, account)
+	}
+	for _, account := range accounts {
+		b.AcctMap.PutAccount(account)
+	}
+
+	for _, mandosNewAddressMock := range step.NewAddressMocks {
+		b.NewAddressMocks = append(b.NewAddressMocks, &NewAddressMock{
+			CreatorAddress: mandosNewAddressMock.CreatorAddress.Value,
+			CreatorNonce:   mandosNewAddressMock.CreatorNonce.Value,
+			NewAddress:     mandosNewAddressMock.NewAddress.Value,
+		})
+	}
+
+	var err error
+	if step.PreviousBlockInfo != nil {
+		b.PreviousBlockInfo, err = blockInfoFromMandos(step.PreviousBlockInfo)
+		if err != nil {
+			return fmt.Errorf("invalid previousBlockInfo: %w", err)
+		}
+	}
+	if step.CurrentBlockInfo != nil {
+		b.CurrentBlockInfo, err = blockInfoFromMandos(step.CurrentBlockInfo)
+		if err != nil {
+			return fmt.Errorf("invalid currentBlockInfo: %w", err)
+		}
+	}
+
+	if len(step.BlockHashes) > 0 || len(step.BlockHashesByNonce) > 0 {
+		b.Blockhashes = make(map[uint64][]byte)
+	}
+	for nonce, blockHash := range step.BlockHashes {
+		b.SetBlockhash(uint64(nonce), blockHash.Value)
+	}
+	for _, blockHash := range step.BlockHashesByNonce {
+		b.SetBlockhash(blockHash.Nonce.Value, blockHash.Hash.Value)
+	}
+
+	return nil
+}
+
+// AccountFromMandos converts a mandos account to a mock account.
+func AccountFromMandos(mandosAccount *mj.Account) (*Account, error) {
+	if mandosAccount.Shard.Value > math.MaxUint32 {
+		return nil, fmt.Errorf("shard does not fit in 32 bits: %d", mandosAccount.Shard.Value)
+	}
+
+	storage := make(map[string][]byte)
+	for _, stkvp := range mandosAccount.Storage {
+		storage[string(stkvp.Key.Value)] = stkvp.Value.Value
+	}
+
+	balance := big.NewInt(0)
+	if mandosAccount.Balance.Value != nil {
+		balance.Set(mandosAccount.Balance.Value)
+	}
+
+	developerReward := big.NewInt(0)
+	if mandosAccount.DeveloperRewards.Value != nil {
+		developerReward.Set(mandosAccount.DeveloperRewards.Value)
+	}
+
+	return &Account{
+		Exists:          true,
+		Address:         mandosAccount.Address.Value,
+		Nonce:           mandosAccount.Nonce.Value,
+		Balance:         balance,
+		DeveloperReward: developerReward,
+		Storage:         storage,
+		Code:            mandosAccount.Code.Value,
+		CodeMetadata:    mandosAccount.CodeMetadata.Value,
+		AsyncCallData:   mandosAccount.AsyncCallData,
+		OwnerAddress:    mandosAccount.Owner.Value,
+		Username:        mandosAccount.Username.Value,
+		ShardID:         uint32(mandosAccount.Shard.Value),
+		IsSmartContract: len(mandosAccount.Code.Value) > 0,
+	}, nil
+}
+
+func blockInfoFromMandos(mandosBlockInfo *mj.BlockInfo) (*BlockInfo, error) {
+	if mandosBlockInfo.BlockEpoch.Value > math.MaxUint32 {
+		return nil, fmt.Errorf("block epoch does not fit in 32 bits: %d", mandosBlockInfo.BlockEpoch.Value)
+	}
+	return &BlockInfo{
+		BlockTimestamp: mandosBlockInfo.BlockTimestamp.Value,
+		BlockNonce:     mandosBlockInfo.BlockNonce.Value,
+		BlockRound:     mandosBlockInfo.BlockRound.Value,
+		BlockEpoch:     uint32(mandosBlockInfo.BlockEpoch.Value),
+		RandomSeed:     mandosBlockInfo.BlockRandomSeed.Value,
+	}, nil
+}
+
+func (bi *BlockInfo) toMandosBlockInfo() *mj.BlockInfo {
+	return &mj.BlockInfo{
+		BlockTimestamp:  mj.JSONUint64FromUint64(bi.BlockTimestamp),
+		BlockNonce:      mj.JSONUint64FromUint64(bi.BlockNonce),
+		BlockRound:      mj.JSONUint64FromUint64(bi.BlockRound),
+		BlockEpoch:      mj.JSONUint64FromUint64(uint64(bi.BlockEpoch)),
+		BlockRandomSeed: mj.JSONBytesFromBytes(bi.RandomSeed),
+	}
+}
+
+// ExportSetStateStep converts the entire mock state to a mandos setState step,
+// that recreates it when applied to an empty mock.
+func (b *BlockchainHookMock) ExportSetStateStep() *mj.SetStateStep {
+	step := &mj.SetStateStep{
+		Accounts: b.ExportAccounts(),
+	}
+	for _, newAddressMock := range b.NewAddressMocks {
+		step.NewAddressMocks = append(step.NewAddressMocks, &mj.NewAddressMock{
+			CreatorAddress: mj.JSONBytesFromBytes(newAddressMock.CreatorAddress),
+			CreatorNonce:   mj.JSONUint64FromUint64(newAddressMock.CreatorNonce),
+			NewAddress:     mj.JSONBytesFromBytes(newAddressMock.NewAddress),
+		})
+	}
+	if b.PreviousBlockInfo != nil {
+		step.PreviousBlockInfo = b.PreviousBlockInfo.toMandosBlockInfo()
+	}
+	if b.CurrentBlockInfo != nil {
+		step.CurrentBlockInfo = b.CurrentBlockInfo.toMandosBlockInfo()
+	}
+	nonces := make([]uint64, 0, len(b.Blockhashes))
+	for nonce := range b.Blockhashes {
+		nonces = append(nonces, nonce)
+	}
+	sort.Slice(nonces, func(i, j int) bool {
+		return nonces[i] < nonces[j]
+	})
+	for _, nonce := range nonces {
+		step.BlockHashesByNonce = append(step.BlockHashesByNonce, &mj.BlockHash{
+			Nonce: mj.JSONUint64FromUint64(nonce),
+			Hash:  mj.JSONBytesFromBytes(b.Blockhashes[nonce]),
+		})
+	}
+	return step
+}
+
+// ExportCheckAccounts converts all accounts in the mock to mandos account checks,
+// that only pass for the exact current state: no other accounts allowed, no "*" values.
+func (b *BlockchainHookMock) ExportCheckAccounts() *mj.CheckAccounts {
+	checkAccounts := &mj.CheckAccounts{
+		OtherAccountsAllowed: false,
+	}
+	for _, mandosAccount := range b.ExportAccounts() {
+		// zero shard and developer rewards are omitted from accounts, but need to be explicit in checks
+		shard := mj.JSONUint64FromUint64(mandosAccount.Shard.Value)
+		developerRewards := mj.JSONBigIntFromBigInt(mandosAccount.DeveloperRewards.Value)
+		checkAccounts.Accounts = append(checkAccounts.Accounts, &mj.CheckAccount{
+			Address: mandosAccount.Address,
+			Nonce: mj.JSONCheckUint64{
+				Value:    mandosAccount.Nonce.Value,
+				Original: mandosAccount.Nonce.Original,
+			},
+			Balance: mj.JSONCheckBigInt{
+				Value:    mandosAccount.Balance.Value,
+				Original: mandosAccount.Balance.Original,
+			},
+			IgnoreStorage: false,
+			CheckStorage:  mandosAccount.Storage,
+			Code: mj.JSONCheckBytes{
+				Value:    mandosAccount.Code.Value,
+				Original: mandosAccount.Code.Original,
+			},
+			AsyncCallData: mandosAccount.AsyncCallData,
+			Owner:         exactCheckBytes(mandosAccount.Owner),
+			Username:      exactCheckBytes(mandosAccount.Username),
+			CodeMetadata:  exactCheckBytes(mandosAccount.CodeMetadata),
+			Shard: mj.JSONCheckUint64{
+				Value:    shard.Value,
+				Original: shard.Original,
+			},
+			DeveloperRewards: mj.JSONCheckBigInt{
+				Value:    developerRewards.Value,
+				Original: developerRewards.Original,
+			},
+		})
+	}
+	return checkAccounts
+}
+
+func exactCheckBytes(jb mj.JSONBytes) mj.JSONCheckBytes {
+	return mj.JSONCheckBytes{
+		Value:    jb.Value,
+		Original: jb.Original,
+	}
+}
+
+// ExportAccounts converts all accounts in the mock to the mandos account format.
+// Accounts are sorted by address and storage entries by key, so the result is deterministic.
+func (b *BlockchainHookMock) ExportAccounts() []*mj.Account {
+	var accounts []*mj.Account
+	for _, acct := range b.AcctMap {
+		accounts = append(accounts, acct.toMandosAccount())
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Address.Value, accounts[j].Address.Value) < 0
+	})
+	return accounts
+}
+
+func (a *Account) toMandosAccount() *mj.Account {
+	balance := big.NewInt(0)
+	if a.Balance != nil {
+		// copy, the mock sometimes updates balances in place
+		balance.Set(a.Balance)
+	}
+	mandosAccount := &mj.Account{
+		Address:       mj.JSONBytesFromBytes(a.Address),
+		Nonce:         mj.JSONUint64FromUint64(a.Nonce),
+		Balance:       mj.JSONBigIntFromBigInt(balance),
+		Storage:       a.mandosStorage(),
+		Code:          mj.JSONBytesFromBytes(a.Code),
+		AsyncCallData: a.AsyncCallData,
+		Owner:         mj.JSONBytesFromBytes(a.OwnerAddress),
+		Username:      mj.JSONBytesFromBytes(a.Username),
+		CodeMetadata:  mj.JSONCodeMetadataFromBytes(a.CodeMetadata),
+	}
+	// shard and developer rewards are only exported when not zero, to keep the output short
+	if a.ShardID != 0 {
+		mandosAccount.Shard = mj.JSONUint64FromUint64(uint64(a.ShardID))
+	}
+	if a.DeveloperReward != nil && a.DeveloperReward.Sign() != 0 {
+		mandosAccount.DeveloperRewards = mj.JSONBigIntFromBigInt(big.NewInt(0).Set(a.DeveloperReward))
+	}
+	return mandosAccount
+}
+
+func (a *Account) mandosStorage() []*mj.StorageKeyValuePair {
+	keys := make([]string, 0, len(a.Storage))
+	for key, value := range a.Storage {
+		if len(value) > 0 {
+			// empty values are equivalent to missing keys
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	storage := make([]*mj.StorageKeyValuePair, 0, len(keys))
+	for _, key := range keys {
+		storage = append(storage, &mj.StorageKeyValuePair{
+			Key:   mj.JSONBytesFromBytes([]byte(key)),
+			Value: mj.JSONBytesFromBytes(a.Storage[key]),
+		})
+	}
+	return storage
+}
+
+// CheckAsyncCalls verifies the recorded asynchronous calls against the checks in a mandos checkState step.
+func (b *BlockchainHookMock) CheckAsyncCalls(checkAsyncCalls *mj.CheckAsyncCalls) error {
+	if !checkAsyncCalls.IgnorePending {
+		err := checkAsyncCallList("pending", checkAsyncCalls.Pending, b.PendingAsyncCalls())
+		if err != nil {
+			return err
+		}
+	}
+	if !checkAsyncCalls.IgnoreCompleted {
+		err := checkAsyncCallList("completed", checkAsyncCalls.Completed, b.CompletedAsyncCalls())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkAsyncCallList(listName string, expected []*mj.CheckAsyncCall, actual []*AsyncCall) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("wrong number of %s async calls. Want: %d. Have: %d", listName, len(expected), len(actual))
+	}
+	for i, check := range expected {
+		err := checkAsyncCall(check, actual[i])
+		if err != nil {
+			return fmt.Errorf("mismatch for %s async call #%d: %w", listName, i, err)
+		}
+	}
+	return nil
+}
+
+func checkAsyncCall(check *mj.CheckAsyncCall, asyncCall *AsyncCall) error {
+	if !check.From.Check(asyncCall.Caller) {
+		return fmt.Errorf("bad from. Want: %s. Have: 0x%s", check.From.Original, hex.EncodeToString(asyncCall.Caller))
+	}
+	if !check.To.Check(asyncCall.Destination) {
+		return fmt.Errorf("bad to. Want: %s. Have: 0x%s", check.To.Original, hex.EncodeToString(asyncCall.Destination))
+	}
+	if len(check.Function) > 0 && check.Function != asyncCall.Function {
+		return fmt.Errorf("bad function. Want: %s. Have: %s", check.Function, asyncCall.Function)
+	}
+	if !check.IgnoreArguments && !checkBytesList(check.Arguments, asyncCall.Arguments) {
+		return fmt.Errorf("bad arguments. Want: %s. Have: %s",
+			mj.JSONCheckBytesString(check.Arguments), mj.ResultAsString(asyncCall.Arguments))
+	}
+	if !check.Value.Check(asyncCall.Value) {
+		return fmt.Errorf("bad value. Want: %s. Have: %d", check.Value.Original, asyncCall.Value)
+	}
+	if !check.GasLimit.Check(asyncCall.GasLimit) {
+		return fmt.Errorf("bad gasLimit. Want: %s. Have: %d", check.GasLimit.Original, asyncCall.GasLimit)
+	}
+	if len(check.Callback) > 0 && check.Callback != asyncCall.Callback {
+		return fmt.Errorf("bad callback. Want: %s. Have: %s", check.Callback, asyncCall.Callback)
+	}
+
+	if check.Status.IsStar && check.IgnoreOut {
+		return nil
+	}
+	if asyncCall.Result == nil {
+		return errors.New("status and out checked, but the call was not executed yet")
+	}
+	if !check.Status.Check(big.NewInt(int64(asyncCall.Result.ReturnCode))) {
+		return fmt.Errorf("bad status. Want: %s. Have: %d", check.Status.Original, asyncCall.Result.ReturnCode)
+	}
+	if !check.IgnoreOut && !checkBytesList(check.Out, asyncCall.Result.ReturnData) {
+		return fmt.Errorf("bad out. Want: %s. Have: %s",
+			mj.JSONCheckBytesString(check.Out), mj.ResultAsString(asyncCall.Result.ReturnData))
+	}
+	return nil
+}
+
+func checkBytesList(checks []mj.JSONCheckBytes, values [][]byte) bool {
+	if len(checks) != len(values) {
+		return false
+	}
+	for i, check := range checks {
+		if !check.Check(values[i]) {
+			return false
+		}
+	}
+	return true
+}
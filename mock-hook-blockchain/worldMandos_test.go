@@ -0,0 +1,175 @@
+package callbackblockchain
+
+import (
+	"math/big"
+	"testing"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+	mjwrite "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/write"
+	"github.com/stretchr/testify/require"
+)
+
+const setStateSnippet = `
+{
+	"step": "setState",
+	"accounts": {
+		"''account_1_______________________": {
+			"nonce": "3",
+			"balance": "1,000",
+			"storage": {
+				"''key1": "''value1",
+				"''key2": "5"
+			},
+			"code": "",
+			"asyncCallData": "some data",
+			"username": "''alice.dme"
+		},
+		"''contract________________________": {
+			"nonce": "0",
+			"balance": "0",
+			"storage": {},
+			"code": "0x0061736d",
+			"owner": "''account_1_______________________",
+			"codeMetadata": "upgradeable|payable",
+			"shard": "1",
+			"developerRewards": "50"
+		}
+	},
+	"newAddresses": [
+		{
+			"creatorAddress": "''account_1_______________________",
+			"creatorNonce": "3",
+			"newAddress": "''contract________________________"
+		}
+	],
+	"previousBlockInfo": {
+		"blockNonce": "10",
+		"blockEpoch": "1"
+	},
+	"currentBlockInfo": {
+		"blockTimestamp": "500",
+		"blockNonce": "11",
+		"blockRound": "12",
+		"blockEpoch": "2",
+		"blockRandomSeed": "0xabcd"
+	},
+	"blockHashes": [
+		"0x1234",
+		"0x5678"
+	]
+}`
+
+func parseSetStateSnippet(t *testing.T, snippet string) *mj.SetStateStep {
+	p := mjparse.Parser{}
+	step, err := p.ParseScenarioStep(snippet)
+	require.Nil(t, err)
+	setStateStep, isSetState := step.(*mj.SetStateStep)
+	require.True(t, isSetState)
+	return setStateStep
+}
+
+func TestApplySetStateStep(t *testing.T) {
+	world := NewMock()
+	err := world.ApplySetStateStep(parseSetStateSnippet(t, setStateSnippet))
+	require.Nil(t, err)
+
+	acct := world.AcctMap.GetAccount([]byte("account_1_______________________"))
+	require.NotNil(t, acct)
+	require.True(t, acct.Exists)
+	require.Equal(t, uint64(3), acct.Nonce)
+	require.Equal(t, big.NewInt(1000), acct.Balance)
+	require.Equal(t, []byte("value1"), acct.StorageValue("key1"))
+	require.Equal(t, []byte{5}, acct.StorageValue("key2"))
+	require.Equal(t, "some data", acct.AsyncCallData)
+	require.False(t, acct.IsSmartContract)
+
+	contract := world.AcctMap.GetAccount([]byte("contract________________________"))
+	require.NotNil(t, contract)
+	require.True(t, contract.IsSmartContract)
+	require.Equal(t, []byte("account_1_______________________"), contract.OwnerAddress)
+	require.Equal(t, []byte{0x01, 0x02}, contract.CodeMetadata)
+	require.Equal(t, uint32(1), contract.ShardID)
+	require.Equal(t, big.NewInt(50), contract.GetDeveloperReward())
+	require.Equal(t, []byte("alice.dme"), acct.Username)
+
+	require.Len(t, world.NewAddressMocks, 1)
+	require.Equal(t, &BlockInfo{BlockNonce: 10, BlockEpoch: 1}, world.PreviousBlockInfo)
+	require.Equal(t, uint64(500), world.CurrentTimeStamp())
+	require.Equal(t, uint64(12), world.CurrentRound())
+	require.Equal(t, []byte{0xab, 0xcd}, world.CurrentRandomSeed())
+	require.Equal(t, map[uint64][]byte{0: {0x12, 0x34}, 1: {0x56, 0x78}}, world.Blockhashes)
+}
+
+func TestApplySetStateStepShardOverflow(t *testing.T) {
+	world := NewMock()
+	err := world.ApplySetStateStep(parseSetStateSnippet(t, `{
+		"step": "setState",
+		"accounts": {
+			"''account_1_______________________": {
+				"nonce": "0"
+			},
+			"''account_2_______________________": {
+				"nonce": "0",
+				"shard": "0x100000000"
+			}
+		}
+	}`))
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "shard does not fit in 32 bits")
+	require.Nil(t, world.AcctMap.GetAccount([]byte("account_1_______________________")))
+}
+
+func TestExportSetStateStepRoundTrip(t *testing.T) {
+	world := NewMock()
+	err := world.ApplySetStateStep(parseSetStateSnippet(t, setStateSnippet))
+	require.Nil(t, err)
+
+	// serialize and parse back, then apply to a fresh mock
+	exported := &mj.Scenario{CheckGas: true, Steps: []mj.Step{world.ExportSetStateStep()}}
+	p := mjparse.Parser{}
+	reparsed, err := p.ParseScenarioFile([]byte(mjwrite.ScenarioToJSONString(exported)))
+	require.Nil(t, err)
+
+	reloaded := NewMock()
+	err = reloaded.ApplySetStateStep(reparsed.Steps[0].(*mj.SetStateStep))
+	require.Nil(t, err)
+	require.Equal(t, world, reloaded)
+}
+
+func TestExportCheckAccounts(t *testing.T) {
+	world := NewMock()
+	err := world.ApplySetStateStep(parseSetStateSnippet(t, setStateSnippet))
+	require.Nil(t, err)
+
+	checkAccounts := world.ExportCheckAccounts()
+	require.False(t, checkAccounts.OtherAccountsAllowed)
+	require.Len(t, checkAccounts.Accounts, 2)
+	for _, checkAccount := range checkAccounts.Accounts {
+		acct := world.AcctMap.GetAccount(checkAccount.Address.Value)
+		require.True(t, checkAccount.Nonce.Check(acct.Nonce))
+		require.True(t, checkAccount.Balance.Check(acct.Balance))
+		require.True(t, checkAccount.Code.Check(acct.Code))
+		require.True(t, checkAccount.Owner.Check(acct.OwnerAddress))
+		require.True(t, checkAccount.Username.Check(acct.Username))
+		require.True(t, checkAccount.CodeMetadata.Check(acct.CodeMetadata))
+		require.True(t, checkAccount.Shard.Check(uint64(acct.ShardID)))
+		require.True(t, checkAccount.DeveloperRewards.Check(acct.GetDeveloperReward()))
+		require.False(t, checkAccount.Nonce.Check(acct.Nonce+1))
+	}
+
+	// the check is exact, empty owners and usernames get written too
+	written := mjwrite.ScenarioToJSONString(&mj.Scenario{
+		Steps: []mj.Step{&mj.CheckStateStep{CheckAccounts: checkAccounts}},
+	})
+	require.Contains(t, written, `"owner": ""`)
+	require.Contains(t, written, `"username": ""`)
+	p := mjparse.Parser{}
+	reparsed, err := p.ParseScenarioFile([]byte(written))
+	require.Nil(t, err)
+	for _, checkAccount := range reparsed.Steps[0].(*mj.CheckStateStep).CheckAccounts.Accounts {
+		require.False(t, checkAccount.Owner.IsStar)
+		require.False(t, checkAccount.Username.IsStar)
+		require.False(t, checkAccount.CodeMetadata.IsStar)
+	}
+}
@@ -0,0 +1,242 @@
+package callbackblockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+	"github.com/kalyan3104/dme-vm-common/parsers"
+)
+
+// CrossShardMessage is a transfer or a smart contract call between accounts in different shards.
+// It is created when the sender's shard processes the transaction
+// and delivered to the destination shard on the next simulated block.
+type CrossShardMessage struct {
+	SenderShard      uint32
+	DestinationShard uint32
+	Sender           []byte
+	Receiver         []byte
+	Value            *big.Int
+
+	// Data holds the call, as "function@hexArg1@hexArg2...". It is empty for plain transfers.
+	Data     []byte
+	GasLimit uint64
+	CallType vmi.CallType
+
+	// SentInBlock is the nonce of the sender shard's block in which the message was created.
+	SentInBlock uint64
+}
+
+// IsCall is true if the message is a smart contract call, not just a transfer.
+func (msg *CrossShardMessage) IsCall() bool {
+	return len(msg.Data) > 0
+}
+
+// FunctionAndArguments parses the call data of the message.
+func (msg *CrossShardMessage) FunctionAndArguments() (string, [][]byte, error) {
+	return parsers.NewCallArgsParser().ParseData(string(msg.Data))
+}
+
+// MultiShardMock simulates several shards, each with its own accounts and block info.
+// Within a shard everything executes synchronously, as in the regular mock.
+// Transfers and calls to other shards are queued and only get delivered when the next block is produced.
+type MultiShardMock struct {
+	Coordinator     ShardCoordinator
+	Shards          []*BlockchainHookMock
+	PendingMessages []*CrossShardMessage
+}
+
+// NewMultiShardMock creates a mock with the given number of shards,
+// that assigns addresses to shards the same way the protocol does.
+func NewMultiShardMock(numberOfShards uint32) (*MultiShardMock, error) {
+	coordinator, err := NewMultiShardCoordinator(numberOfShards)
+	if err != nil {
+		return nil, err
+	}
+	return NewMultiShardMockWithCoordinator(coordinator), nil
+}
+
+// NewMultiShardMockWithCoordinator creates a multi-shard mock with a custom shard coordinator.
+func NewMultiShardMockWithCoordinator(coordinator ShardCoordinator) *MultiShardMock {
+	m := &MultiShardMock{
+		Coordinator: coordinator,
+	}
+	for shardID := uint32(0); shardID < coordinator.NumberOfShards(); shardID++ {
+		shard := NewMock()
+		shard.ShardCoordinator = coordinator
+		shard.CurrentBlockInfo = &BlockInfo{}
+		m.Shards = append(m.Shards, shard)
+	}
+	return m
+}
+
+// Clear resets all shards and drops all pending messages.
+func (m *MultiShardMock) Clear() {
+	for _, shard := range m.Shards {
+		shard.Clear()
+	}
+	m.PendingMessages = nil
+}
+
+// ShardOf yields the shard of an address.
+func (m *MultiShardMock) ShardOf(address []byte) uint32 {
+	return m.Coordinator.ComputeShardID(address)
+}
+
+// Shard yields the world of one shard. It is also the blockchain hook to be used by VMs running in that shard.
+func (m *MultiShardMock) Shard(shardID uint32) *BlockchainHookMock {
+	return m.Shards[shardID]
+}
+
+// WorldOf yields the world of the shard where the address belongs.
+func (m *MultiShardMock) WorldOf(address []byte) *BlockchainHookMock {
+	return m.Shards[m.ShardOf(address)]
+}
+
+// PutAccount saves the account in its shard. The account shard ID gets set accordingly.
+func (m *MultiShardMock) PutAccount(acct *Account) {
+	acct.ShardID = m.ShardOf(acct.Address)
+	m.Shards[acct.ShardID].AcctMap.PutAccount(acct)
+}
+
+// GetAccount retrieves an account from its shard.
+func (m *MultiShardMock) GetAccount(address []byte) *Account {
+	return m.WorldOf(address).AcctMap.GetAccount(address)
+}
+
+// Transfer moves value between 2 accounts.
+// Within the same shard the transfer is immediate.
+// Across shards, the sender is debited immediately, but the receiver is only credited on the next block.
+func (m *MultiShardMock) Transfer(sender []byte, receiver []byte, value *big.Int) error {
+	if value == nil || value.Sign() < 0 {
+		return errors.New("transfer value must be a non-negative number")
+	}
+	senderAcct := m.GetAccount(sender)
+	if senderAcct == nil {
+		return errors.New("transfer sender does not exist")
+	}
+	if senderAcct.Balance.Cmp(value) < 0 {
+		return errors.New("insufficient funds for transfer")
+	}
+
+	senderShard := m.ShardOf(sender)
+	err := m.Shards[senderShard].UpdateBalanceWithDelta(sender, big.NewInt(0).Neg(value))
+	if err != nil {
+		return err
+	}
+
+	receiverShard := m.ShardOf(receiver)
+	if receiverShard == senderShard {
+		m.Shards[receiverShard].creditAccount(receiver, value)
+		return nil
+	}
+
+	m.queueMessage(&CrossShardMessage{
+		SenderShard:      senderShard,
+		DestinationShard: receiverShard,
+		Sender:           sender,
+		Receiver:         receiver,
+		Value:            big.NewInt(0).Set(value),
+	})
+	return nil
+}
+
+// UpdateAccounts applies the output of a VM that ran in the shard of the caller.
+// Output accounts from the same shard are updated immediately.
+// Output accounts from other shards become cross-shard messages,
+// carrying the positive balance delta, the call data and the gas limit.
+// The whole output is validated first, so an invalid output changes nothing and queues no messages.
+func (m *MultiShardMock) UpdateAccounts(
+	modifiedAccounts []*vmi.OutputAccount,
+	accountsToDelete [][]byte,
+	callerAddress []byte) error {
+
+	callerShard := m.ShardOf(callerAddress)
+	var sameShardAccounts []*vmi.OutputAccount
+	var otherShardAccounts []*vmi.OutputAccount
+	for _, modAcct := range modifiedAccounts {
+		destinationShard := m.ShardOf(modAcct.Address)
+		if destinationShard == callerShard {
+			sameShardAccounts = append(sameShardAccounts, modAcct)
+			continue
+		}
+		if len(modAcct.StorageUpdates) > 0 || len(modAcct.Code) > 0 {
+			return fmt.Errorf("cannot change storage or code of account in shard %d from shard %d", destinationShard, callerShard)
+		}
+		otherShardAccounts = append(otherShardAccounts, modAcct)
+	}
+	for _, delAddr := range accountsToDelete {
+		if m.ShardOf(delAddr) != callerShard {
+			return errors.New("cannot delete account from another shard")
+		}
+	}
+
+	err := m.Shards[callerShard].UpdateAccounts(sameShardAccounts, accountsToDelete, callerAddress)
+	if err != nil {
+		return err
+	}
+
+	for _, modAcct := range otherShardAccounts {
+		value := big.NewInt(0)
+		if modAcct.BalanceDelta != nil && modAcct.BalanceDelta.Sign() > 0 {
+			value.Set(modAcct.BalanceDelta)
+		}
+		m.queueMessage(&CrossShardMessage{
+			SenderShard:      callerShard,
+			DestinationShard: m.ShardOf(modAcct.Address),
+			Sender:           callerAddress,
+			Receiver:         modAcct.Address,
+			Value:            value,
+			Data:             modAcct.Data,
+			GasLimit:         modAcct.GasLimit,
+			CallType:         modAcct.CallType,
+		})
+	}
+	return nil
+}
+
+func (m *MultiShardMock) queueMessage(msg *CrossShardMessage) {
+	msg.SentInBlock = m.Shards[msg.SenderShard].CurrentNonce()
+	m.PendingMessages = append(m.PendingMessages, msg)
+}
+
+// NextBlock produces a new block in all shards and delivers all messages pending so far, in the order they were sent.
+// Transferred value is credited to the receivers.
+// The returned messages are the delivered smart contract calls; executors are expected to run them in the destination shard.
+// Messages created while running them stay pending until the next block.
+func (m *MultiShardMock) NextBlock() []*CrossShardMessage {
+	for _, shard := range m.Shards {
+		shard.AdvanceBlock(1)
+	}
+
+	delivered := m.PendingMessages
+	m.PendingMessages = nil
+
+	var calls []*CrossShardMessage
+	for _, msg := range delivered {
+		m.Shards[msg.DestinationShard].creditAccount(msg.Receiver, msg.Value)
+		if msg.IsCall() {
+			calls = append(calls, msg)
+		}
+	}
+	return calls
+}
+
+// creditAccount adds value to an account, creating it if it does not exist.
+func (b *BlockchainHookMock) creditAccount(address []byte, value *big.Int) {
+	acct := b.AcctMap.GetAccount(address)
+	if acct == nil {
+		acct = &Account{
+			Exists:  true,
+			Address: address,
+			Balance: big.NewInt(0),
+			Storage: make(map[string][]byte),
+		}
+		if b.ShardCoordinator != nil {
+			acct.ShardID = b.ShardCoordinator.ComputeShardID(address)
+		}
+		b.AcctMap.PutAccount(acct)
+	}
+	acct.Balance = big.NewInt(0).Add(acct.Balance, value)
+}
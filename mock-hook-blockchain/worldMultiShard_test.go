@@ -0,0 +1,149 @@
+package callbackblockchain
+
+import (
+	"math/big"
+	"testing"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+	"github.com/stretchr/testify/require"
+)
+
+func addressInShard(lastByte byte) []byte {
+	address := make([]byte, 32)
+	copy(address, "address_in_shard")
+	address[31] = lastByte
+	return address
+}
+
+func TestMultiShardCoordinator(t *testing.T) {
+	_, err := NewMultiShardCoordinator(0)
+	require.Equal(t, ErrInvalidNumberOfShards, err)
+	_, err = NewMultiShardMock(0)
+	require.Equal(t, ErrInvalidNumberOfShards, err)
+
+	coordinator, err := NewMultiShardCoordinator(1)
+	require.Nil(t, err)
+	require.Equal(t, uint32(0), coordinator.ComputeShardID(addressInShard(5)))
+
+	coordinator, err = NewMultiShardCoordinator(2)
+	require.Nil(t, err)
+	require.Equal(t, uint32(0), coordinator.ComputeShardID(addressInShard(4)))
+	require.Equal(t, uint32(1), coordinator.ComputeShardID(addressInShard(5)))
+
+	coordinator, err = NewMultiShardCoordinator(3)
+	require.Nil(t, err)
+	require.Equal(t, uint32(0), coordinator.ComputeShardID(addressInShard(0)))
+	require.Equal(t, uint32(1), coordinator.ComputeShardID(addressInShard(1)))
+	require.Equal(t, uint32(2), coordinator.ComputeShardID(addressInShard(2)))
+	require.Equal(t, uint32(1), coordinator.ComputeShardID(addressInShard(3)))
+}
+
+func TestMultiShardTransfer(t *testing.T) {
+	m, err := NewMultiShardMock(2)
+	require.Nil(t, err)
+	sender := addressInShard(0)
+	sameShardReceiver := addressInShard(2)
+	otherShardReceiver := addressInShard(1)
+	m.PutAccount(&Account{Address: sender, Balance: big.NewInt(100), Storage: make(map[string][]byte)})
+
+	err = m.Transfer(sender, sameShardReceiver, nil)
+	require.NotNil(t, err)
+	err = m.Transfer(sender, sameShardReceiver, big.NewInt(-1))
+	require.NotNil(t, err)
+
+	err = m.Transfer(sender, sameShardReceiver, big.NewInt(10))
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(10), m.GetAccount(sameShardReceiver).Balance)
+
+	err = m.Transfer(sender, otherShardReceiver, big.NewInt(20))
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(70), m.GetAccount(sender).Balance)
+	require.Nil(t, m.GetAccount(otherShardReceiver))
+	require.Len(t, m.PendingMessages, 1)
+
+	calls := m.NextBlock()
+	require.Empty(t, calls)
+	require.Empty(t, m.PendingMessages)
+	require.Equal(t, big.NewInt(20), m.GetAccount(otherShardReceiver).Balance)
+	require.Equal(t, uint32(1), m.GetAccount(otherShardReceiver).ShardID)
+	require.Equal(t, uint64(1), m.Shard(0).CurrentNonce())
+	require.Equal(t, uint64(1), m.Shard(1).CurrentNonce())
+	require.Equal(t, uint64(0), m.Shard(1).LastNonce())
+
+	err = m.Transfer(sender, otherShardReceiver, big.NewInt(1000))
+	require.NotNil(t, err)
+}
+
+func TestMultiShardUpdateAccounts(t *testing.T) {
+	m, err := NewMultiShardMock(2)
+	require.Nil(t, err)
+	caller := addressInShard(0)
+	contract := addressInShard(2)
+	otherShardContract := addressInShard(3)
+	m.PutAccount(&Account{Address: caller, Balance: big.NewInt(100), Storage: make(map[string][]byte)})
+	m.PutAccount(&Account{Address: contract, Balance: big.NewInt(0), Storage: make(map[string][]byte)})
+
+	err = m.UpdateAccounts(
+		[]*vmi.OutputAccount{
+			{
+				Address:      contract,
+				BalanceDelta: big.NewInt(-5),
+				StorageUpdates: map[string]*vmi.StorageUpdate{
+					"key": {Offset: []byte("key"), Data: []byte("value")},
+				},
+			},
+			{
+				Address:      otherShardContract,
+				BalanceDelta: big.NewInt(5),
+				Data:         []byte("doSomething@01@02"),
+				GasLimit:     1000,
+				CallType:     vmi.AsynchronousCall,
+			},
+		},
+		nil,
+		caller)
+	require.Nil(t, err)
+	require.Equal(t, []byte("value"), m.GetAccount(contract).StorageValue("key"))
+	require.Nil(t, m.GetAccount(otherShardContract))
+
+	calls := m.NextBlock()
+	require.Len(t, calls, 1)
+	require.Equal(t, uint32(0), calls[0].SenderShard)
+	require.Equal(t, uint32(1), calls[0].DestinationShard)
+	require.Equal(t, uint64(0), calls[0].SentInBlock)
+	function, args, err := calls[0].FunctionAndArguments()
+	require.Nil(t, err)
+	require.Equal(t, "doSomething", function)
+	require.Equal(t, [][]byte{{1}, {2}}, args)
+	require.Equal(t, big.NewInt(5), m.GetAccount(otherShardContract).Balance)
+
+	err = m.UpdateAccounts(
+		[]*vmi.OutputAccount{
+			{
+				Address: otherShardContract,
+				Code:    []byte("code"),
+			},
+		},
+		nil,
+		caller)
+	require.NotNil(t, err)
+
+	// nothing is applied or queued if any account in the output is invalid
+	err = m.UpdateAccounts(
+		[]*vmi.OutputAccount{
+			{
+				Address:      contract,
+				BalanceDelta: big.NewInt(1),
+			},
+			{
+				Address:      otherShardContract,
+				BalanceDelta: big.NewInt(1),
+				Data:         []byte("doSomething"),
+			},
+		},
+		[][]byte{otherShardContract},
+		caller)
+	require.NotNil(t, err)
+	require.Equal(t, big.NewInt(-5), m.GetAccount(contract).Balance)
+	require.Empty(t, m.PendingMessages)
+}
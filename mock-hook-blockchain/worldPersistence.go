@@ -0,0 +1,197 @@
+package callbackblockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+)
+
+// WorldFileFormat identifies files produced by BlockchainHookMock.Save.
+const WorldFileFormat = "mock-world"
+
+// WorldFileVersion is the current version of the world file format.
+// It must be incremented whenever the format changes incompatibly,
+// e.g. when a field gets renamed, which is why all saved types have explicit JSON names.
+const WorldFileVersion = 1
+
+// worldFile is the serialized form of a BlockchainHookMock.
+// Byte slices are encoded as base64 by encoding/json.
+type worldFile struct {
+	Format            string            `json:"format"`
+	Version           int               `json:"version"`
+	Accounts          []*accountFile    `json:"accounts"`
+	PreviousBlockInfo *BlockInfo        `json:"previousBlockInfo,omitempty"`
+	CurrentBlockInfo  *BlockInfo        `json:"currentBlockInfo,omitempty"`
+	Blockhashes       map[uint64][]byte `json:"blockhashes,omitempty"`
+	NewAddressMocks   []*NewAddressMock `json:"newAddressMocks,omitempty"`
+	GasSchedule       *GasSchedule      `json:"gasSchedule,omitempty"`
+	BlockConfig       *BlockConfig      `json:"blockConfig,omitempty"`
+	AsyncCalls        []*AsyncCall      `json:"asyncCalls,omitempty"`
+}
+
+// accountFile is the serialized form of an Account.
+// Storage keys are arbitrary bytes, so storage is saved as a list instead of a JSON map.
+type accountFile struct {
+	Exists          bool               `json:"exists"`
+	Address         []byte             `json:"address"`
+	Nonce           uint64             `json:"nonce"`
+	Balance         *big.Int           `json:"balance"`
+	BalanceDelta    *big.Int           `json:"balanceDelta,omitempty"`
+	DeveloperReward *big.Int           `json:"developerReward,omitempty"`
+	Storage         []*storageFileItem `json:"storage"`
+	Code            []byte             `json:"code"`
+	CodeMetadata    []byte             `json:"codeMetadata"`
+	AsyncCallData   string             `json:"asyncCallData"`
+	OwnerAddress    []byte             `json:"ownerAddress"`
+	Username        []byte             `json:"username"`
+	ShardID         uint32             `json:"shardID"`
+	IsSmartContract bool               `json:"isSmartContract"`
+}
+
+type storageFileItem struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Save writes the complete state of the mock as JSON:
+// accounts with their storage, block infos, block hashes, new address mocks, async calls, gas schedule and block config.
+// The address generator and the shard coordinator are not saved, they need to be set again after loading.
+func (b *BlockchainHookMock) Save(writer io.Writer) error {
+	file := &worldFile{
+		Format:            WorldFileFormat,
+		Version:           WorldFileVersion,
+		Accounts:          make([]*accountFile, 0, len(b.AcctMap)),
+		PreviousBlockInfo: b.PreviousBlockInfo,
+		CurrentBlockInfo:  b.CurrentBlockInfo,
+		Blockhashes:       b.Blockhashes,
+		NewAddressMocks:   b.NewAddressMocks,
+		GasSchedule:       b.GasSchedule,
+		BlockConfig:       b.BlockConfig,
+		AsyncCalls:        b.AsyncCalls,
+	}
+	for _, acct := range b.AcctMap {
+		file.Accounts = append(file.Accounts, acct.toAccountFile())
+	}
+	// map iteration order is random, keep the output deterministic
+	sort.Slice(file.Accounts, func(i, j int) bool {
+		return string(file.Accounts[i].Address) < string(file.Accounts[j].Address)
+	})
+
+	return json.NewEncoder(writer).Encode(file)
+}
+
+// SaveToFile writes the complete state of the mock to a file, see Save.
+func (b *BlockchainHookMock) SaveToFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = b.Save(file)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// Load creates a new mock from the state written by Save.
+func Load(reader io.Reader) (*BlockchainHookMock, error) {
+	file := &worldFile{}
+	err := json.NewDecoder(reader).Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid world file: %w", err)
+	}
+	if file.Format != WorldFileFormat {
+		return nil, errors.New("invalid world file: unknown format")
+	}
+	if file.Version != WorldFileVersion {
+		return nil, fmt.Errorf("unsupported world file version. Want: %d. Have: %d", WorldFileVersion, file.Version)
+	}
+
+	world := NewMock()
+	for _, acctFile := range file.Accounts {
+		world.AcctMap.PutAccount(acctFile.toAccount())
+	}
+	world.PreviousBlockInfo = file.PreviousBlockInfo
+	world.CurrentBlockInfo = file.CurrentBlockInfo
+	if file.Blockhashes != nil {
+		world.Blockhashes = file.Blockhashes
+	}
+	world.NewAddressMocks = file.NewAddressMocks
+	world.GasSchedule = file.GasSchedule
+	world.BlockConfig = file.BlockConfig
+	world.AsyncCalls = file.AsyncCalls
+	return world, nil
+}
+
+// LoadFromFile creates a new mock from a file written by SaveToFile.
+func LoadFromFile(path string) (*BlockchainHookMock, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return Load(file)
+}
+
+func (a *Account) toAccountFile() *accountFile {
+	keys := make([]string, 0, len(a.Storage))
+	for key := range a.Storage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	storage := make([]*storageFileItem, 0, len(keys))
+	for _, key := range keys {
+		storage = append(storage, &storageFileItem{
+			Key:   []byte(key),
+			Value: a.Storage[key],
+		})
+	}
+
+	return &accountFile{
+		Exists:          a.Exists,
+		Address:         a.Address,
+		Nonce:           a.Nonce,
+		Balance:         a.Balance,
+		BalanceDelta:    a.BalanceDelta,
+		DeveloperReward: a.DeveloperReward,
+		Storage:         storage,
+		Code:            a.Code,
+		CodeMetadata:    a.CodeMetadata,
+		AsyncCallData:   a.AsyncCallData,
+		OwnerAddress:    a.OwnerAddress,
+		Username:        a.Username,
+		ShardID:         a.ShardID,
+		IsSmartContract: a.IsSmartContract,
+	}
+}
+
+func (af *accountFile) toAccount() *Account {
+	storage := make(map[string][]byte, len(af.Storage))
+	for _, item := range af.Storage {
+		storage[string(item.Key)] = item.Value
+	}
+
+	return &Account{
+		Exists:          af.Exists,
+		Address:         af.Address,
+		Nonce:           af.Nonce,
+		Balance:         af.Balance,
+		BalanceDelta:    af.BalanceDelta,
+		DeveloperReward: af.DeveloperReward,
+		Storage:         storage,
+		Code:            af.Code,
+		CodeMetadata:    af.CodeMetadata,
+		AsyncCallData:   af.AsyncCallData,
+		OwnerAddress:    af.OwnerAddress,
+		Username:        af.Username,
+		ShardID:         af.ShardID,
+		IsSmartContract: af.IsSmartContract,
+	}
+}
@@ -0,0 +1,67 @@
+package callbackblockchain
+
+import (
+	"bytes"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	world := NewMock()
+	err := world.ApplySetStateStep(parseSetStateSnippet(t, setStateSnippet))
+	require.Nil(t, err)
+	world.AcctMap.GetAccount([]byte("account_1_______________________")).Storage["\x00\xff binary key"] = []byte{1, 2}
+	world.GasSchedule = &GasSchedule{MinGasLimit: 10, GasPerDataByte: 1, DeveloperFeePercentage: 30}
+	world.BlockConfig = &BlockConfig{RoundsPerEpoch: 100}
+	world.AdvanceBlock(2)
+	_, err = world.RecordAsyncCalls([]byte("contract________________________"), []*vmi.OutputAccount{
+		{
+			Address:      []byte("account_1_______________________"),
+			BalanceDelta: big.NewInt(1),
+			Data:         []byte("callMe@01"),
+			CallType:     vmi.AsynchronousCall,
+		},
+	})
+	require.Nil(t, err)
+	world.AsyncCalls[0].SetResult(vmi.Ok, [][]byte{{5}})
+
+	path := filepath.Join(t.TempDir(), "world.json")
+	err = world.SaveToFile(path)
+	require.Nil(t, err)
+	loaded, err := LoadFromFile(path)
+	require.Nil(t, err)
+	require.Equal(t, world, loaded)
+
+	// output is deterministic
+	var first, second bytes.Buffer
+	require.Nil(t, world.Save(&first))
+	require.Nil(t, loaded.Save(&second))
+	require.Equal(t, first.String(), second.String())
+
+	// the saved names do not depend on the Go field names
+	for _, name := range []string{`"blockNonce":`, `"randomSeed":`, `"creatorAddress":`, `"destination":`, `"returnCode":`, `"roundsPerEpoch":`} {
+		require.Contains(t, first.String(), name)
+	}
+}
+
+func TestLoadVersionCheck(t *testing.T) {
+	var buffer bytes.Buffer
+	err := NewMock().Save(&buffer)
+	require.Nil(t, err)
+
+	_, err = Load(strings.NewReader(buffer.String()))
+	require.Nil(t, err)
+
+	otherVersion := strings.Replace(buffer.String(), `"version":1`, `"version":2`, 1)
+	_, err = Load(strings.NewReader(otherVersion))
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "unsupported world file version")
+
+	_, err = Load(strings.NewReader(`{"version":1}`))
+	require.NotNil(t, err)
+}
@@ -48,7 +48,9 @@ func (b *BlockchainHookMock) UpdateWorldStateBefore(
 	return nil
 }
 
-// UpdateAccounts should be called after the VM test has run, to update world state
+// UpdateAccounts should be called after the VM test has run, to update world state.
+// AsyncCallData only keeps the data of the latest call to each account, as in the old test format.
+// The queue of asynchronous calls is kept in AsyncCalls, by UpdateWorldStateAfter.
 func (b *BlockchainHookMock) UpdateAccounts(
 	modifiedAccounts []*vmi.OutputAccount,
 	accountsToDelete [][]byte,
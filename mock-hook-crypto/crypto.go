@@ -5,6 +5,7 @@ import (
 
 	"crypto/sha256"
 
+	"golang.org/x/crypto/blake2b"
 	"golang.org/x/crypto/ripemd160"
 	"golang.org/x/crypto/sha3"
 )
@@ -39,6 +40,18 @@ func (KryptoHookMock) Ripemd160(data []byte) ([]byte, error) {
 	return result, nil
 }
 
+// Blake2b cryptographic function, with a 32 byte output
+func (KryptoHookMock) Blake2b(data []byte) ([]byte, error) {
+	result := blake2b.Sum256(data)
+	return result[:], nil
+}
+
+// Sha3 cryptographic function, the standard SHA3-256 (unlike Keccak256, which uses the original padding)
+func (KryptoHookMock) Sha3(data []byte) ([]byte, error) {
+	result := sha3.Sum256(data)
+	return result[:], nil
+}
+
 // Ecrecover calculates the corresponding Ethereum address for the public key which created the given signature
 func (KryptoHookMock) Ecrecover(hash []byte, recoveryID []byte, r []byte, s []byte) ([]byte, error) {
 	fmt.Println(">>>>> Ecrecover")
@@ -0,0 +1,152 @@
+package mockhookcrypto
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// ErrInvalidSignature signals that a well-formed signature does not match the key and message.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Secp256k1RawSignatureLength is the length of a signature given as r and s, 32 bytes each.
+const Secp256k1RawSignatureLength = 64
+
+// BLSPublicKeyLength is the length of a compressed BLS public key, a point in G2.
+const BLSPublicKeyLength = 96
+
+// BLSSignatureLength is the length of a compressed BLS signature, a point in G1.
+const BLSSignatureLength = 48
+
+// BLSDomain is the domain separation tag used when hashing messages to the curve.
+// Signatures live in G1 and public keys in G2 ("minimal signature size" variant).
+const BLSDomain = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_"
+
+// VerifyEd25519 checks an ed25519 signature of the message. It returns nil if the signature is valid.
+func (KryptoHookMock) VerifyEd25519(key []byte, msg []byte, sig []byte) error {
+	if len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("ed25519 public key must be %d bytes long, got %d", ed25519.PublicKeySize, len(key))
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("ed25519 signature must be %d bytes long, got %d", ed25519.SignatureSize, len(sig))
+	}
+	if !ed25519.Verify(key, msg, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifySecp256k1 checks an ECDSA secp256k1 signature of the sha256 hash of the message.
+// The key can be compressed or uncompressed.
+// The signature can be either raw (r and s, 32 bytes each) or DER encoded.
+// It returns nil if the signature is valid.
+func (KryptoHookMock) VerifySecp256k1(key []byte, msg []byte, sig []byte) error {
+	pubKey, err := secp256k1.ParsePubKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+	signature, err := parseSecp256k1Signature(sig)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(msg)
+	if !signature.Verify(hash[:], pubKey) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func parseSecp256k1Signature(sig []byte) (*ecdsa.Signature, error) {
+	if len(sig) != Secp256k1RawSignatureLength {
+		signature, err := ecdsa.ParseDERSignature(sig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secp256k1 DER signature: %w", err)
+		}
+		return signature, nil
+	}
+
+	var r, s secp256k1.ModNScalar
+	rOverflow := r.SetByteSlice(sig[:32])
+	sOverflow := s.SetByteSlice(sig[32:])
+	if rOverflow || sOverflow || r.IsZero() || s.IsZero() {
+		return nil, errors.New("invalid secp256k1 raw signature: r and s must be in the range [1, N-1]")
+	}
+	return ecdsa.NewSignature(&r, &s), nil
+}
+
+// VerifyBLS checks a BLS12-381 signature of the message. It returns nil if the signature is valid.
+// Note: this is a pure-Go implementation, with the standard hash-to-curve;
+// keys and signatures are not byte-compatible with the ones used by the protocol.
+func (k KryptoHookMock) VerifyBLS(key []byte, msg []byte, sig []byte) error {
+	return k.VerifyAggregatedBLS([][]byte{key}, msg, sig)
+}
+
+// VerifyAggregatedBLS checks an aggregated BLS12-381 signature, produced by several signers of the same message.
+// It returns nil if the signature is valid. Same compatibility remark as for VerifyBLS.
+func (KryptoHookMock) VerifyAggregatedBLS(keys [][]byte, msg []byte, aggregatedSig []byte) error {
+	if len(keys) == 0 {
+		return errors.New("no BLS public keys provided")
+	}
+
+	g2 := bls12381.NewG2()
+	aggregatedKey := g2.Zero()
+	for i, key := range keys {
+		pubKey, err := parseBLSPublicKey(g2, key)
+		if err != nil {
+			return fmt.Errorf("invalid BLS public key at index %d: %w", i, err)
+		}
+		g2.Add(aggregatedKey, aggregatedKey, pubKey)
+	}
+
+	g1 := bls12381.NewG1()
+	signature, err := parseBLSSignature(g1, aggregatedSig)
+	if err != nil {
+		return err
+	}
+	hash, err := g1.HashToCurve(msg, []byte(BLSDomain))
+	if err != nil {
+		return err
+	}
+
+	// e(sig, g2) == e(H(msg), pk)
+	engine := bls12381.NewEngine()
+	engine.AddPair(signature, g2.One())
+	engine.AddPairInv(hash, aggregatedKey)
+	if !engine.Check() {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func parseBLSPublicKey(g2 *bls12381.G2, key []byte) (*bls12381.PointG2, error) {
+	if len(key) != BLSPublicKeyLength {
+		return nil, fmt.Errorf("must be %d bytes long, got %d", BLSPublicKeyLength, len(key))
+	}
+	pubKey, err := g2.FromCompressed(key)
+	if err != nil {
+		return nil, err
+	}
+	if g2.IsZero(pubKey) || !g2.InCorrectSubgroup(pubKey) {
+		return nil, errors.New("point not in the G2 subgroup")
+	}
+	return pubKey, nil
+}
+
+func parseBLSSignature(g1 *bls12381.G1, sig []byte) (*bls12381.PointG1, error) {
+	if len(sig) != BLSSignatureLength {
+		return nil, fmt.Errorf("BLS signature must be %d bytes long, got %d", BLSSignatureLength, len(sig))
+	}
+	signature, err := g1.FromCompressed(sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BLS signature: %w", err)
+	}
+	if !g1.InCorrectSubgroup(signature) {
+		return nil, errors.New("invalid BLS signature: point not in the G1 subgroup")
+	}
+	return signature, nil
+}
@@ -0,0 +1,78 @@
+package mockhookcrypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testMessage = []byte("message to sign")
+
+func TestVerifyEd25519(t *testing.T) {
+	privateKey, publicKey := Ed25519KeyFromSeed("alice")
+	samePrivateKey, _ := Ed25519KeyFromSeed("alice")
+	require.Equal(t, privateKey, samePrivateKey)
+
+	sig, err := SignEd25519(privateKey, testMessage)
+	require.Nil(t, err)
+	require.Nil(t, KryptoHookMockInstance.VerifyEd25519(publicKey, testMessage, sig))
+	require.Equal(t, ErrInvalidSignature, KryptoHookMockInstance.VerifyEd25519(publicKey, []byte("other message"), sig))
+
+	_, otherPublicKey := Ed25519KeyFromSeed("bob")
+	require.Equal(t, ErrInvalidSignature, KryptoHookMockInstance.VerifyEd25519(otherPublicKey, testMessage, sig))
+	require.NotNil(t, KryptoHookMockInstance.VerifyEd25519(publicKey[1:], testMessage, sig))
+}
+
+func TestVerifySecp256k1(t *testing.T) {
+	privateKey, publicKey := Secp256k1KeyFromSeed("alice")
+
+	rawSig := SignSecp256k1(privateKey, testMessage)
+	require.Len(t, rawSig, Secp256k1RawSignatureLength)
+	require.Nil(t, KryptoHookMockInstance.VerifySecp256k1(publicKey, testMessage, rawSig))
+
+	derSig := SignSecp256k1DER(privateKey, testMessage)
+	require.Equal(t, byte(0x30), derSig[0])
+	require.Nil(t, KryptoHookMockInstance.VerifySecp256k1(publicKey, testMessage, derSig))
+
+	require.Equal(t, ErrInvalidSignature, KryptoHookMockInstance.VerifySecp256k1(publicKey, []byte("other message"), rawSig))
+	_, otherPublicKey := Secp256k1KeyFromSeed("bob")
+	require.Equal(t, ErrInvalidSignature, KryptoHookMockInstance.VerifySecp256k1(otherPublicKey, testMessage, derSig))
+
+	require.NotNil(t, KryptoHookMockInstance.VerifySecp256k1(publicKey, testMessage, derSig[1:]))
+	require.NotNil(t, KryptoHookMockInstance.VerifySecp256k1(publicKey, testMessage, make([]byte, Secp256k1RawSignatureLength)))
+	require.NotNil(t, KryptoHookMockInstance.VerifySecp256k1([]byte("not a key"), testMessage, rawSig))
+}
+
+func TestVerifyBLS(t *testing.T) {
+	privateKey, publicKey := BLSKeyFromSeed("alice")
+	require.Len(t, publicKey, BLSPublicKeyLength)
+
+	sig, err := SignBLS(privateKey, testMessage)
+	require.Nil(t, err)
+	require.Len(t, sig, BLSSignatureLength)
+	require.Nil(t, KryptoHookMockInstance.VerifyBLS(publicKey, testMessage, sig))
+	require.Equal(t, ErrInvalidSignature, KryptoHookMockInstance.VerifyBLS(publicKey, []byte("other message"), sig))
+
+	_, otherPublicKey := BLSKeyFromSeed("bob")
+	require.Equal(t, ErrInvalidSignature, KryptoHookMockInstance.VerifyBLS(otherPublicKey, testMessage, sig))
+	require.NotNil(t, KryptoHookMockInstance.VerifyBLS(publicKey, testMessage, sig[1:]))
+}
+
+func TestVerifyAggregatedBLS(t *testing.T) {
+	var publicKeys, signatures [][]byte
+	for _, seed := range []string{"alice", "bob", "carol"} {
+		privateKey, publicKey := BLSKeyFromSeed(seed)
+		sig, err := SignBLS(privateKey, testMessage)
+		require.Nil(t, err)
+		publicKeys = append(publicKeys, publicKey)
+		signatures = append(signatures, sig)
+	}
+
+	aggregatedSig, err := AggregateBLSSignatures(signatures)
+	require.Nil(t, err)
+	require.Nil(t, KryptoHookMockInstance.VerifyAggregatedBLS(publicKeys, testMessage, aggregatedSig))
+
+	// one signer missing
+	require.Equal(t, ErrInvalidSignature, KryptoHookMockInstance.VerifyAggregatedBLS(publicKeys[:2], testMessage, aggregatedSig))
+	require.NotNil(t, KryptoHookMockInstance.VerifyAggregatedBLS(nil, testMessage, aggregatedSig))
+}
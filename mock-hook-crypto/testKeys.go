@@ -0,0 +1,99 @@
+package mockhookcrypto
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// The functions below derive key pairs deterministically from a seed, e.g. the name of a test account,
+// so tests and scenarios can produce signatures that the Verify functions accept.
+// They are not meant for anything other than tests.
+
+func seedHash(seed string) []byte {
+	hash := sha256.Sum256([]byte(seed))
+	return hash[:]
+}
+
+// Ed25519KeyFromSeed yields an ed25519 private key (64 bytes) and public key (32 bytes) derived from the seed.
+func Ed25519KeyFromSeed(seed string) (privateKey []byte, publicKey []byte) {
+	key := ed25519.NewKeyFromSeed(seedHash(seed))
+	return key, key.Public().(ed25519.PublicKey)
+}
+
+// SignEd25519 signs the message with a private key obtained from Ed25519KeyFromSeed.
+func SignEd25519(privateKey []byte, msg []byte) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid ed25519 private key length")
+	}
+	return ed25519.Sign(privateKey, msg), nil
+}
+
+// Secp256k1KeyFromSeed yields a secp256k1 private key (32 bytes) and compressed public key (33 bytes) derived from the seed.
+func Secp256k1KeyFromSeed(seed string) (privateKey []byte, publicKey []byte) {
+	key := secp256k1.PrivKeyFromBytes(seedHash(seed))
+	return key.Serialize(), key.PubKey().SerializeCompressed()
+}
+
+// SignSecp256k1 signs the sha256 hash of the message, yielding a raw signature: r and s, 32 bytes each.
+func SignSecp256k1(privateKey []byte, msg []byte) []byte {
+	hash := sha256.Sum256(msg)
+	// the compact format is the recovery code, followed by r and s
+	compact := ecdsa.SignCompact(secp256k1.PrivKeyFromBytes(privateKey), hash[:], true)
+	return compact[1:]
+}
+
+// SignSecp256k1DER signs the sha256 hash of the message, yielding a DER encoded signature.
+func SignSecp256k1DER(privateKey []byte, msg []byte) []byte {
+	hash := sha256.Sum256(msg)
+	return ecdsa.Sign(secp256k1.PrivKeyFromBytes(privateKey), hash[:]).Serialize()
+}
+
+// BLSKeyFromSeed yields a BLS12-381 private key (32 bytes) and compressed public key (96 bytes) derived from the seed.
+func BLSKeyFromSeed(seed string) (privateKey []byte, publicKey []byte) {
+	g1 := bls12381.NewG1()
+	scalar := new(big.Int).SetBytes(seedHash(seed))
+	scalar.Mod(scalar, g1.Q())
+
+	g2 := bls12381.NewG2()
+	pubKey := g2.New()
+	g2.MulScalarBig(pubKey, g2.One(), scalar)
+
+	privateKey = make([]byte, 32)
+	scalar.FillBytes(privateKey)
+	return privateKey, g2.ToCompressed(pubKey)
+}
+
+// SignBLS signs the message with a private key obtained from BLSKeyFromSeed, yielding a compressed signature (48 bytes).
+func SignBLS(privateKey []byte, msg []byte) ([]byte, error) {
+	g1 := bls12381.NewG1()
+	hash, err := g1.HashToCurve(msg, []byte(BLSDomain))
+	if err != nil {
+		return nil, err
+	}
+	signature := g1.New()
+	g1.MulScalarBig(signature, hash, new(big.Int).SetBytes(privateKey))
+	return g1.ToCompressed(signature), nil
+}
+
+// AggregateBLSSignatures combines signatures of the same message into one, to be checked with VerifyAggregatedBLS.
+func AggregateBLSSignatures(signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no BLS signatures to aggregate")
+	}
+	g1 := bls12381.NewG1()
+	aggregated := g1.Zero()
+	for _, sig := range signatures {
+		signature, err := parseBLSSignature(g1, sig)
+		if err != nil {
+			return nil, err
+		}
+		g1.Add(aggregated, aggregated, signature)
+	}
+	return g1.ToCompressed(aggregated), nil
+}
@@ -0,0 +1,157 @@
+package mandoscontroller
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	vmi "github.com/kalyan3104/dme-vm-common"
+	worldmock "github.com/kalyan3104/dme-vm-util/mock-hook-blockchain"
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardingStepExecutor simulates a contract that forwards each call asynchronously:
+// the first argument is the destination, the function and the remaining arguments are forwarded as they are.
+type forwardingStepExecutor struct {
+	world *worldmock.BlockchainHookMock
+}
+
+func (e *forwardingStepExecutor) Reset() {}
+
+func (e *forwardingStepExecutor) ExecuteScenario(_ *mj.Scenario, _ mjparse.FileResolver) error {
+	return nil
+}
+
+func (e *forwardingStepExecutor) ExecuteSetState(_ *ExecutionContext, step *mj.SetStateStep) error {
+	return e.world.ApplySetStateStep(step)
+}
+
+func (e *forwardingStepExecutor) ExecuteTx(_ *ExecutionContext, step *mj.TxStep) error {
+	tx := step.Tx
+	err := e.world.UpdateWorldStateBefore(tx.From.Value, tx.GasLimit.Value, tx.GasPrice.Value)
+	if err != nil {
+		return err
+	}
+
+	callData := []string{tx.Function}
+	for _, arg := range tx.Arguments[1:] {
+		callData = append(callData, hex.EncodeToString(arg.Value))
+	}
+	destination := tx.Arguments[0].Value
+	asyncCallAccount := &vmi.OutputAccount{
+		Address:      destination,
+		BalanceDelta: big.NewInt(0),
+		Data:         []byte(strings.Join(callData, "@")),
+		GasLimit:     tx.GasLimit.Value / 4,
+		CallType:     vmi.AsynchronousCall,
+	}
+	vmOutput := &vmi.VMOutput{
+		GasRemaining:   tx.GasLimit.Value / 2,
+		OutputAccounts: map[string]*vmi.OutputAccount{string(destination): asyncCallAccount},
+	}
+	err = e.world.UpdateAccounts([]*vmi.OutputAccount{asyncCallAccount}, nil, tx.From.Value)
+	if err != nil {
+		return err
+	}
+	return e.world.UpdateWorldStateAfter(tx.From.Value, tx.To.Value, nil, tx.GasLimit.Value, tx.GasPrice.Value, vmOutput)
+}
+
+func (e *forwardingStepExecutor) ExecuteCheckState(_ *ExecutionContext, step *mj.CheckStateStep) error {
+	if step.CheckAsyncCalls == nil {
+		return nil
+	}
+	return e.world.CheckAsyncCalls(step.CheckAsyncCalls)
+}
+
+func TestAsyncCallsScenario(t *testing.T) {
+	scenarioPath := filepath.Join(t.TempDir(), "async.scen.json")
+	require.Nil(t, ioutil.WriteFile(scenarioPath, []byte(`{
+		"name": "async",
+		"steps": [
+			{
+				"step": "setState",
+				"accounts": {
+					"''sender__________________________": {
+						"nonce": "0",
+						"balance": "1,000,000",
+						"storage": {},
+						"code": ""
+					},
+					"''forwarder_______________________": {
+						"nonce": "0",
+						"balance": "0",
+						"storage": {},
+						"code": "''forwarder code"
+					}
+				}
+			},
+			{
+				"step": "scCall",
+				"txId": "1",
+				"tx": {
+					"from": "''sender__________________________",
+					"to": "''forwarder_______________________",
+					"value": "0",
+					"function": "first",
+					"arguments": [ "''destination_____________________", "1" ],
+					"gasLimit": "1000",
+					"gasPrice": "1"
+				}
+			},
+			{
+				"step": "scCall",
+				"txId": "2",
+				"tx": {
+					"from": "''sender__________________________",
+					"to": "''forwarder_______________________",
+					"value": "0",
+					"function": "second",
+					"arguments": [ "''destination_____________________", "2" ],
+					"gasLimit": "1000",
+					"gasPrice": "1"
+				}
+			},
+			{
+				"step": "checkState",
+				"accounts": { "+": "" },
+				"asyncCalls": {
+					"pending": [
+						{
+							"from": "''forwarder_______________________",
+							"to": "''destination_____________________",
+							"function": "first",
+							"arguments": [ "1" ],
+							"gasLimit": "250",
+							"callback": "firstCallback"
+						},
+						{
+							"function": "second",
+							"arguments": [ "2" ],
+							"callback": "secondCallback"
+						}
+					],
+					"completed": []
+				}
+			}
+		]
+	}`), 0644))
+
+	world := worldmock.NewMock()
+	world.AsyncCallbackFunction = func(asyncCall *worldmock.AsyncCall) string {
+		return asyncCall.Function + "Callback"
+	}
+	runner := NewScenarioRunner(&forwardingStepExecutor{world: world}, NewDefaultFileResolver())
+	err := runner.RunSingleJSONScenario(scenarioPath)
+	require.Nil(t, err)
+	require.Len(t, world.PendingAsyncCalls(), 2)
+
+	world.PendingAsyncCalls()[0].SetResult(vmi.Ok, nil)
+	world.PendingAsyncCalls()[0].SetCallbackResult(vmi.Ok, nil)
+	require.Len(t, world.CompletedAsyncCalls(), 1)
+	require.Equal(t, "second", world.PendingAsyncCalls()[0].Function)
+}
@@ -0,0 +1,142 @@
+package mandoscontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrScenarioTimeout signals that a scenario or test did not finish within the configured timeout.
+var ErrScenarioTimeout = errors.New("scenario timed out")
+
+// ErrScenarioAbandoned signals a timeout after which the scenario or test did not stop, even after a grace period.
+// It is still running in the background and still uses the executor, so the runner must not be used any more.
+// It also matches ErrScenarioTimeout.
+var ErrScenarioAbandoned error = abandonedError{}
+
+type abandonedError struct{}
+
+func (abandonedError) Error() string {
+	return "scenario timed out and did not stop, it is still running"
+}
+
+func (abandonedError) Is(target error) bool {
+	return target == ErrScenarioTimeout
+}
+
+// timeoutGracePeriod is how long a scenario gets to stop once its context is done,
+// before it is considered abandoned.
+const timeoutGracePeriod = time.Second
+
+// errStopWalk is used internally to interrupt the directory walk.
+var errStopWalk = errors.New("stop walk")
+
+// RunOptions configures the context-aware directory runners.
+type RunOptions struct {
+	// Timeout is the maximum duration of a single scenario/test file. Zero means no timeout.
+	Timeout time.Duration
+
+	// FailFast stops the run after this many failures (timeouts included). Zero means never stop early.
+	FailFast int
+
+	// Retries is how many more times a failed or timed out file is run before it counts as a failure.
+	// Zero means no retries.
+	Retries int
+}
+
+// RunSummary holds the outcome of a context-aware directory run.
+// Timed out files are kept separately from the ones that failed with an error.
+type RunSummary struct {
+	Passed   []string
+	Failed   []string
+	TimedOut []string
+	Skipped  []string
+
+	// Retried lists the passed files that failed at least once before passing.
+	Retried []string
+
+	// Interrupted is true if the run stopped early, due to fail-fast, context cancellation or an abandoned scenario.
+	Interrupted bool
+}
+
+// NrFailures yields the number of failed and timed out files.
+func (s *RunSummary) NrFailures() int {
+	return len(s.Failed) + len(s.TimedOut)
+}
+
+// String yields a human readable summary.
+func (s *RunSummary) String() string {
+	str := fmt.Sprintf("Passed: %d. Failed: %d. Timed out: %d. Skipped: %d.",
+		len(s.Passed), len(s.Failed), len(s.TimedOut), len(s.Skipped))
+	for _, retried := range s.Retried {
+		str += "\n  passed after retrying: " + retried
+	}
+	for _, timedOut := range s.TimedOut {
+		str += "\n  timed out: " + timedOut
+	}
+	for _, failed := range s.Failed {
+		str += "\n  failed: " + failed
+	}
+	if s.Interrupted {
+		str += "\n  run interrupted, not all files were executed"
+	}
+	return str
+}
+
+func (s *RunSummary) shouldStop(options RunOptions) bool {
+	return options.FailFast > 0 && s.NrFailures() >= options.FailFast
+}
+
+// shouldRetry is false for abandoned files, which still use the executor, and for cancelled runs.
+func shouldRetry(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil && !errors.Is(err, ErrScenarioAbandoned)
+}
+
+// runWithTimeout runs the callback in a separate goroutine and waits for it,
+// until the context is done or the timeout (if any) expires.
+// The callback receives the derived context and should stop when it is done.
+// Once the context is done, the callback still gets a grace period to return,
+// so that the executor is no longer in use when the next scenario starts.
+// Callbacks that ignore the context are abandoned and keep running in the background, which yields ErrScenarioAbandoned.
+func runWithTimeout(parentCtx context.Context, timeout time.Duration, callback func(context.Context) error) error {
+	ctx := parentCtx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parentCtx, timeout)
+		defer cancel()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- callback(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+	}
+
+	stopped := waitForResult(result, timeoutGracePeriod)
+	if parentCtx.Err() != nil {
+		// the whole run was cancelled, not just this file
+		return parentCtx.Err()
+	}
+	if !stopped {
+		return ErrScenarioAbandoned
+	}
+	return ErrScenarioTimeout
+}
+
+// waitForResult yields false if nothing arrived in time.
+func waitForResult(result chan error, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-result:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -1,6 +1,7 @@
 package mandoscontroller
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -50,3 +51,28 @@ func (r *ScenarioRunner) RunAllJSONScenariosInDirectory(
 
 	return nil
 }
+
+// RunAllJSONScenariosInDirectoryWithContext walks directory, parses and prepares all json scenarios,
+// then calls scenarioExecutor for each of them, observing the context and the run options.
+// It returns a summary of the run, plus an error if any scenario failed or timed out, or if the context got cancelled.
+func (r *ScenarioRunner) RunAllJSONScenariosInDirectoryWithContext(
+	ctx context.Context,
+	generalTestPath string,
+	specificTestPath string,
+	allowedSuffix string,
+	excludedFilePatterns []string,
+	options RunOptions) (*RunSummary, error) {
+
+	return runAllInDirectoryWithContext(
+		ctx,
+		"Scenario",
+		generalTestPath,
+		specificTestPath,
+		allowedSuffix,
+		excludedFilePatterns,
+		options,
+		func(ctx context.Context, scenarioFilePath string) error {
+			r.Executor.Reset()
+			return r.RunSingleJSONScenarioWithContext(ctx, scenarioFilePath, options.Timeout)
+		})
+}
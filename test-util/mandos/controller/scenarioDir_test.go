@@ -0,0 +1,220 @@
+package mandoscontroller
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+	"github.com/stretchr/testify/require"
+)
+
+// dummyExecutor behaves according to the scenario name suffix.
+type dummyExecutor struct {
+	nrExecuted int32
+	nrFlaky    int32
+}
+
+func (e *dummyExecutor) Reset() {}
+
+func (e *dummyExecutor) ExecuteScenario(scenario *mj.Scenario, _ mjparse.FileResolver) error {
+	return e.ExecuteScenarioWithContext(context.Background(), scenario, nil)
+}
+
+func (e *dummyExecutor) ExecuteScenarioWithContext(ctx context.Context, scenario *mj.Scenario, _ mjparse.FileResolver) error {
+	atomic.AddInt32(&e.nrExecuted, 1)
+	switch {
+	case strings.HasSuffix(scenario.Name, "fail"):
+		return errors.New("failed")
+	case strings.HasSuffix(scenario.Name, "flaky"):
+		// only the first run fails
+		if atomic.AddInt32(&e.nrFlaky, 1) == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	case strings.HasSuffix(scenario.Name, "hang"):
+		<-ctx.Done()
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
+
+func writeDummyScenarios(t *testing.T, names ...string) string {
+	dir := t.TempDir()
+	for _, name := range names {
+		contents := `{ "name": "` + name + `", "steps": [] }`
+		err := ioutil.WriteFile(filepath.Join(dir, name+".scen.json"), []byte(contents), 0644)
+		require.Nil(t, err)
+	}
+	return dir
+}
+
+func TestRunWithContextSummary(t *testing.T) {
+	dir := writeDummyScenarios(t, "a_ok", "b_fail", "c_hang", "d_ok")
+
+	executor := &dummyExecutor{}
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	summary, err := runner.RunAllJSONScenariosInDirectoryWithContext(
+		context.Background(),
+		dir,
+		"",
+		".scen.json",
+		nil,
+		RunOptions{Timeout: 50 * time.Millisecond})
+	require.NotNil(t, err)
+	require.Equal(t, []string{"a_ok.scen.json", "d_ok.scen.json"}, summary.Passed)
+	require.Equal(t, []string{"b_fail.scen.json"}, summary.Failed)
+	require.Equal(t, []string{"c_hang.scen.json"}, summary.TimedOut)
+	require.False(t, summary.Interrupted)
+	require.Equal(t, int32(4), atomic.LoadInt32(&executor.nrExecuted))
+}
+
+func TestRunWithContextFailFast(t *testing.T) {
+	dir := writeDummyScenarios(t, "fail", "ok")
+
+	executor := &dummyExecutor{}
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	summary, err := runner.RunAllJSONScenariosInDirectoryWithContext(
+		context.Background(),
+		dir,
+		"",
+		".scen.json",
+		nil,
+		RunOptions{FailFast: 1})
+	require.NotNil(t, err)
+	require.Equal(t, []string{"fail.scen.json"}, summary.Failed)
+	require.Empty(t, summary.Passed)
+	require.True(t, summary.Interrupted)
+	require.Equal(t, int32(1), atomic.LoadInt32(&executor.nrExecuted))
+}
+
+func TestRunWithContextRetries(t *testing.T) {
+	dir := writeDummyScenarios(t, "a_flaky", "b_fail", "c_ok")
+
+	executor := &dummyExecutor{}
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	summary, err := runner.RunAllJSONScenariosInDirectoryWithContext(
+		context.Background(),
+		dir,
+		"",
+		".scen.json",
+		nil,
+		RunOptions{Retries: 2})
+	require.NotNil(t, err)
+	require.Equal(t, []string{"a_flaky.scen.json", "c_ok.scen.json"}, summary.Passed)
+	require.Equal(t, []string{"a_flaky.scen.json"}, summary.Retried)
+	require.Equal(t, []string{"b_fail.scen.json"}, summary.Failed)
+	// the flaky scenario runs twice, the failing one 3 times
+	require.Equal(t, int32(6), atomic.LoadInt32(&executor.nrExecuted))
+
+	// without retries, the flaky scenario fails
+	executor = &dummyExecutor{}
+	runner = NewScenarioRunner(executor, NewDefaultFileResolver())
+	summary, err = runner.RunAllJSONScenariosInDirectoryWithContext(
+		context.Background(),
+		dir,
+		"",
+		".scen.json",
+		nil,
+		RunOptions{})
+	require.NotNil(t, err)
+	require.Equal(t, []string{"a_flaky.scen.json", "b_fail.scen.json"}, summary.Failed)
+	require.Empty(t, summary.Retried)
+}
+
+func TestRunWithContextCancelled(t *testing.T) {
+	dir := writeDummyScenarios(t, "ok")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	executor := &dummyExecutor{}
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	summary, err := runner.RunAllJSONScenariosInDirectoryWithContext(
+		ctx,
+		dir,
+		"",
+		".scen.json",
+		nil,
+		RunOptions{})
+	require.True(t, errors.Is(err, context.Canceled))
+	require.True(t, summary.Interrupted)
+	require.Equal(t, int32(0), atomic.LoadInt32(&executor.nrExecuted))
+}
+
+// sharedStateExecutor uses its state both in Reset and while executing, like a real executor would.
+type sharedStateExecutor struct {
+	state   int
+	release chan struct{}
+}
+
+func (e *sharedStateExecutor) Reset() {
+	e.state = 0
+}
+
+func (e *sharedStateExecutor) ExecuteScenario(scenario *mj.Scenario, _ mjparse.FileResolver) error {
+	return e.ExecuteScenarioWithContext(context.Background(), scenario, nil)
+}
+
+func (e *sharedStateExecutor) ExecuteScenarioWithContext(ctx context.Context, scenario *mj.Scenario, _ mjparse.FileResolver) error {
+	switch {
+	case strings.HasSuffix(scenario.Name, "slow_stop"):
+		// stops, but only after cleaning up
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		e.state++
+		return ctx.Err()
+	case strings.HasSuffix(scenario.Name, "ignore_ctx"):
+		<-e.release
+		e.state++
+		return nil
+	default:
+		e.state++
+		return nil
+	}
+}
+
+func TestRunWithContextTimeoutNoRace(t *testing.T) {
+	dir := writeDummyScenarios(t, "a_slow_stop", "b_ok")
+
+	executor := &sharedStateExecutor{}
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	summary, err := runner.RunAllJSONScenariosInDirectoryWithContext(
+		context.Background(),
+		dir,
+		"",
+		".scen.json",
+		nil,
+		RunOptions{Timeout: 20 * time.Millisecond})
+	require.NotNil(t, err)
+	require.Equal(t, []string{"a_slow_stop.scen.json"}, summary.TimedOut)
+	require.Equal(t, []string{"b_ok.scen.json"}, summary.Passed)
+	require.False(t, summary.Interrupted)
+	require.Equal(t, 1, executor.state)
+}
+
+func TestRunWithContextAbandoned(t *testing.T) {
+	dir := writeDummyScenarios(t, "a_ignore_ctx", "b_ok")
+
+	executor := &sharedStateExecutor{release: make(chan struct{})}
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	summary, err := runner.RunAllJSONScenariosInDirectoryWithContext(
+		context.Background(),
+		dir,
+		"",
+		".scen.json",
+		nil,
+		RunOptions{Timeout: 20 * time.Millisecond})
+	require.NotNil(t, err)
+	require.Equal(t, []string{"a_ignore_ctx.scen.json"}, summary.TimedOut)
+	require.Empty(t, summary.Passed)
+	require.True(t, summary.Interrupted)
+	close(executor.release)
+}
@@ -1,20 +1,61 @@
 package mandoscontroller
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
 	mjwrite "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/write"
 )
 
 // RunSingleJSONScenario parses and prepares test, then calls testCallback.
+// If the executor implements StepExecutor, the runner executes the scenario step by step.
 func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string) error {
+	scenario, scenarioPath, err := r.parseScenarioFile(contextPath)
+	if err != nil {
+		return err
+	}
+
+	if stepExecutor, isStepExecutor := r.Executor.(StepExecutor); isStepExecutor {
+		return r.executeScenarioSteps(context.Background(), stepExecutor, scenario, scenarioPath)
+	}
+	return r.Executor.ExecuteScenario(scenario, r.Parser.FileResolver)
+}
+
+// RunSingleJSONScenarioWithContext parses and prepares the scenario, then executes it.
+// It returns early if the context is done, or with ErrScenarioTimeout if the timeout expires (0 means no timeout).
+// If it returns ErrScenarioAbandoned, the executor is still in use and the runner must not be used again.
+func (r *ScenarioRunner) RunSingleJSONScenarioWithContext(
+	ctx context.Context,
+	contextPath string,
+	timeout time.Duration) error {
+
+	scenario, scenarioPath, err := r.parseScenarioFile(contextPath)
+	if err != nil {
+		return err
+	}
+
+	fileResolver := r.Parser.FileResolver
+	return runWithTimeout(ctx, timeout, func(ctx context.Context) error {
+		if stepExecutor, isStepExecutor := r.Executor.(StepExecutor); isStepExecutor {
+			return r.executeScenarioSteps(ctx, stepExecutor, scenario, scenarioPath)
+		}
+		if ctxExecutor, ok := r.Executor.(ScenarioExecutorWithContext); ok {
+			return ctxExecutor.ExecuteScenarioWithContext(ctx, scenario, fileResolver)
+		}
+		return r.Executor.ExecuteScenario(scenario, fileResolver)
+	})
+}
+
+// parseScenarioFile also returns the absolute path of the scenario file.
+func (r *ScenarioRunner) parseScenarioFile(contextPath string) (*mj.Scenario, string, error) {
 	var err error
 	contextPath, err = filepath.Abs(contextPath)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	// Open our jsonFile
@@ -22,7 +63,7 @@ func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string) error {
 	jsonFile, err = os.Open(contextPath)
 	// if we os.Open returns an error then handle it
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	// defer the closing of our jsonFile so that we can parse it later on
@@ -30,16 +71,14 @@ func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string) error {
 
 	byteValue, err := ioutil.ReadAll(jsonFile)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	r.Parser.FileResolver.SetContext(contextPath)
-	scenario, parseErr := r.Parser.ParseScenarioFile(byteValue)
-	if parseErr != nil {
-		return parseErr
-	}
-
-	return r.Executor.ExecuteScenario(scenario, r.Parser.FileResolver)
+	r.Parser.StartFile(contextPath)
+	scenario, err := r.Parser.ParseScenarioFile(byteValue)
+	printParseWarnings(r.Parser.Warnings)
+	return scenario, contextPath, err
 }
 
 // tool to modify scenarios
@@ -0,0 +1,34 @@
+package mandoscontroller
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithParseWarnings(t *testing.T) {
+	dir := t.TempDir()
+	duplicatePath := filepath.Join(dir, "duplicate.scen.json")
+	err := ioutil.WriteFile(duplicatePath, []byte(`{ "name": "first", "name": "second", "steps": [] }`), 0644)
+	require.Nil(t, err)
+	cleanPath := filepath.Join(dir, "clean.scen.json")
+	err = ioutil.WriteFile(cleanPath, []byte(`{ "name": "clean", "steps": [] }`), 0644)
+	require.Nil(t, err)
+
+	runner := NewScenarioRunner(&dummyExecutor{}, NewDefaultFileResolver())
+	err = runner.RunSingleJSONScenario(duplicatePath)
+	require.NotNil(t, err)
+
+	runner.Parser.AllowDuplicates = true
+	err = runner.RunSingleJSONScenario(duplicatePath)
+	require.Nil(t, err)
+	require.Len(t, runner.Parser.Warnings, 1)
+	require.Equal(t, duplicatePath, runner.Parser.Warnings[0].File)
+	require.Contains(t, runner.Parser.Warnings[0].Error(), "duplicate map key \"name\"")
+
+	err = runner.RunSingleJSONScenario(cleanPath)
+	require.Nil(t, err)
+	require.Empty(t, runner.Parser.Warnings)
+}
@@ -1,6 +1,8 @@
 package mandoscontroller
 
 import (
+	"context"
+
 	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
 	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
 )
@@ -12,14 +14,24 @@ type ScenarioExecutor interface {
 
 	// ExecuteScenario executes the scenario and checks if it passed. Failure is signaled by returning an error.
 	// The FileResolver helps with resolving external steps.
-	// TODO: group into a "execution context" param.
+	// Executors that also implement StepExecutor are not called here, the runner executes the steps itself.
 	ExecuteScenario(*mj.Scenario, mjparse.FileResolver) error
 }
 
+// ScenarioExecutorWithContext is an optional extension of ScenarioExecutor.
+// Executors implementing it get notified when a scenario times out or the run gets cancelled,
+// otherwise they are simply abandoned.
+type ScenarioExecutorWithContext interface {
+	// ExecuteScenarioWithContext executes the scenario, just like ExecuteScenario,
+	// but should return as soon as possible once the context is done.
+	ExecuteScenarioWithContext(context.Context, *mj.Scenario, mjparse.FileResolver) error
+}
+
 // ScenarioRunner is a component that can run json scenarios, using a provided executor.
 type ScenarioRunner struct {
-	Executor ScenarioExecutor
-	Parser   mjparse.Parser
+	Executor  ScenarioExecutor
+	Parser    mjparse.Parser
+	StepHooks []StepHook
 }
 
 // NewScenarioRunner creates new ScenarioRunner instance.
@@ -31,3 +43,10 @@ func NewScenarioRunner(executor ScenarioExecutor, fileResolver mjparse.FileResol
 		},
 	}
 }
+
+// AddStepHook registers a hook to be called around each step.
+// Hooks only work with executors that implement StepExecutor.
+func (r *ScenarioRunner) AddStepHook(hook StepHook) *ScenarioRunner {
+	r.StepHooks = append(r.StepHooks, hook)
+	return r
+}
@@ -0,0 +1,136 @@
+package mandoscontroller
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+)
+
+// executeScenarioSteps runs all steps of the scenario through the step executor, calling the step hooks around each of them.
+// It stops at the first failed step, or when the context is done.
+func (r *ScenarioRunner) executeScenarioSteps(
+	ctx context.Context,
+	stepExecutor StepExecutor,
+	scenario *mj.Scenario,
+	scenarioPath string) error {
+
+	steps, err := r.expandExternalSteps(scenario.Steps, scenarioPath)
+	if err != nil {
+		return err
+	}
+
+	for stepIndex, step := range steps {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		execCtx := r.newExecutionContext(scenario, scenarioPath, stepIndex)
+		err := r.executeStep(stepExecutor, execCtx, step)
+		if err != nil {
+			return fmt.Errorf("step %s failed: %w", stepName(stepIndex, step), err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ScenarioRunner) newExecutionContext(scenario *mj.Scenario, scenarioPath string, stepIndex int) *ExecutionContext {
+	return &ExecutionContext{
+		Scenario:     scenario,
+		ScenarioPath: scenarioPath,
+		FileResolver: r.Parser.FileResolver,
+		StepIndex:    stepIndex,
+	}
+}
+
+// executeStep dispatches a single step to the executor, between the before and after hooks.
+func (r *ScenarioRunner) executeStep(stepExecutor StepExecutor, execCtx *ExecutionContext, step mj.Step) error {
+	for _, hook := range r.StepHooks {
+		err := hook.BeforeStep(execCtx, step)
+		if err != nil {
+			return err
+		}
+	}
+
+	var stepErr error
+	switch specificStep := step.(type) {
+	case *mj.SetStateStep:
+		stepErr = stepExecutor.ExecuteSetState(execCtx, specificStep)
+	case *mj.TxStep:
+		stepErr = stepExecutor.ExecuteTx(execCtx, specificStep)
+	case *mj.CheckStateStep:
+		stepErr = stepExecutor.ExecuteCheckState(execCtx, specificStep)
+	case *mj.NextBlockStep:
+		nextBlockExecutor, canExecute := stepExecutor.(NextBlockExecutor)
+		if canExecute {
+			stepErr = nextBlockExecutor.ExecuteNextBlock(execCtx, specificStep)
+		} else {
+			stepErr = fmt.Errorf("step type not supported by the step executor: %s", step.StepTypeName())
+		}
+	default:
+		stepErr = fmt.Errorf("step type not supported by the step executor: %s", step.StepTypeName())
+	}
+
+	for _, hook := range r.StepHooks {
+		hookErr := hook.AfterStep(execCtx, step, stepErr)
+		if hookErr != nil && stepErr == nil {
+			stepErr = hookErr
+		}
+	}
+
+	return stepErr
+}
+
+// expandExternalSteps replaces all external steps with the steps loaded from the referenced files, recursively.
+// Paths are resolved relative to the file that contains them.
+func (r *ScenarioRunner) expandExternalSteps(steps []mj.Step, contextPath string) ([]mj.Step, error) {
+	var result []mj.Step
+	for _, step := range steps {
+		externalStepsStep, isExternal := step.(*mj.ExternalStepsStep)
+		if !isExternal {
+			result = append(result, step)
+			continue
+		}
+
+		r.Parser.FileResolver.SetContext(contextPath)
+		externalPath := r.Parser.FileResolver.ResolveAbsolutePath(externalStepsStep.Path)
+		externalPath, err := filepath.Abs(externalPath)
+		if err != nil {
+			return nil, err
+		}
+		byteValue, err := ioutil.ReadFile(externalPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load external steps %s: %w", externalStepsStep.Path, err)
+		}
+
+		r.Parser.FileResolver.SetContext(externalPath)
+		r.Parser.CurrentFile = externalPath
+		nrWarnings := len(r.Parser.Warnings)
+		externalScenario, err := r.Parser.ParseScenarioFile(byteValue)
+		printParseWarnings(r.Parser.Warnings[nrWarnings:])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse external steps %s: %w", externalStepsStep.Path, err)
+		}
+
+		externalSteps, err := r.expandExternalSteps(externalScenario.Steps, externalPath)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, externalSteps...)
+	}
+
+	r.Parser.FileResolver.SetContext(contextPath)
+	r.Parser.CurrentFile = contextPath
+	return result, nil
+}
+
+// stepName yields a readable name for a step, that can also be used as a subtest name.
+func stepName(stepIndex int, step mj.Step) string {
+	name := fmt.Sprintf("%03d_%s", stepIndex, step.StepTypeName())
+	if txStep, isTx := step.(*mj.TxStep); isTx && len(txStep.TxIdent) > 0 {
+		name += "_" + txStep.TxIdent
+	}
+	return name
+}
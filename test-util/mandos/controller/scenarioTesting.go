@@ -0,0 +1,115 @@
+package mandoscontroller
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// SubtestOptions configures how scenarios get registered as Go subtests.
+type SubtestOptions struct {
+	// StepSubtests registers each scenario step as a nested subtest.
+	// Requires an executor that implements StepExecutor.
+	StepSubtests bool
+
+	// Parallel marks all scenario subtests as parallel.
+	// Executors and file resolvers hold state, so each scenario then gets its own runner, created by NewRunner.
+	Parallel bool
+
+	// NewRunner creates a fresh runner for a scenario. Mandatory when Parallel is set.
+	NewRunner func() *ScenarioRunner
+}
+
+// RunAllJSONScenariosAsSubtests walks directory and registers each json scenario as a subtest of t.
+// Subtest names are the scenario paths relative to generalTestPath, without the suffix,
+// so scenarios can be selected with `go test -run`.
+// Excluded scenarios show up as skipped subtests.
+func (r *ScenarioRunner) RunAllJSONScenariosAsSubtests(
+	t *testing.T,
+	generalTestPath string,
+	specificTestPath string,
+	allowedSuffix string,
+	excludedFilePatterns []string,
+	options SubtestOptions) {
+
+	t.Helper()
+	if options.Parallel && options.NewRunner == nil {
+		t.Fatal("parallel scenario subtests require a NewRunner function")
+	}
+
+	mainDirPath := path.Join(generalTestPath, specificTestPath)
+	err := filepath.Walk(mainDirPath, func(scenarioFilePath string, info os.FileInfo, err error) error {
+		if !strings.HasSuffix(scenarioFilePath, allowedSuffix) {
+			return nil
+		}
+		subtestName := strings.TrimSuffix(shortenTestPath(scenarioFilePath, generalTestPath), allowedSuffix)
+		excluded := isExcluded(excludedFilePatterns, scenarioFilePath, generalTestPath)
+		t.Run(subtestName, func(t *testing.T) {
+			if excluded {
+				t.Skip("scenario excluded")
+			}
+			runner := r
+			if options.Parallel {
+				t.Parallel()
+				runner = options.NewRunner()
+			}
+			runner.runScenarioSubtest(t, scenarioFilePath, options.StepSubtests)
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// RunSingleJSONScenarioAsSubtest registers a single json scenario as a subtest of t,
+// named after the scenario file.
+func (r *ScenarioRunner) RunSingleJSONScenarioAsSubtest(t *testing.T, scenarioFilePath string, stepSubtests bool) bool {
+	t.Helper()
+	return t.Run(filepath.Base(scenarioFilePath), func(t *testing.T) {
+		r.runScenarioSubtest(t, scenarioFilePath, stepSubtests)
+	})
+}
+
+func (r *ScenarioRunner) runScenarioSubtest(t *testing.T, scenarioFilePath string, stepSubtests bool) {
+	t.Helper()
+	r.Executor.Reset()
+
+	if !stepSubtests {
+		err := r.RunSingleJSONScenario(scenarioFilePath)
+		if err != nil {
+			t.Error(err)
+		}
+		return
+	}
+
+	stepExecutor, isStepExecutor := r.Executor.(StepExecutor)
+	if !isStepExecutor {
+		t.Fatal("step subtests require an executor that implements StepExecutor")
+	}
+
+	scenario, scenarioPath, err := r.parseScenarioFile(scenarioFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, err := r.expandExternalSteps(scenario.Steps, scenarioPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for stepIndex, step := range steps {
+		execCtx := r.newExecutionContext(scenario, scenarioPath, stepIndex)
+		passed := t.Run(stepName(stepIndex, step), func(t *testing.T) {
+			stepErr := r.executeStep(stepExecutor, execCtx, step)
+			if stepErr != nil {
+				t.Error(stepErr)
+			}
+		})
+		if !passed {
+			t.Logf("skipping the %d remaining steps", len(steps)-stepIndex-1)
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package mandoscontroller
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingStepExecutor keeps the names of the steps it executed.
+type recordingStepExecutor struct {
+	mutex    sync.Mutex
+	executed []string
+}
+
+func (e *recordingStepExecutor) Reset() {}
+
+func (e *recordingStepExecutor) ExecuteScenario(_ *mj.Scenario, _ mjparse.FileResolver) error {
+	return errors.New("should execute step by step")
+}
+
+func (e *recordingStepExecutor) ExecuteSetState(execCtx *ExecutionContext, step *mj.SetStateStep) error {
+	e.record(execCtx.Scenario.Name + ":" + step.StepTypeName())
+	return nil
+}
+
+func (e *recordingStepExecutor) ExecuteTx(execCtx *ExecutionContext, step *mj.TxStep) error {
+	e.record(execCtx.Scenario.Name + ":" + step.StepTypeName())
+	return nil
+}
+
+func (e *recordingStepExecutor) ExecuteCheckState(execCtx *ExecutionContext, step *mj.CheckStateStep) error {
+	e.record(execCtx.Scenario.Name + ":" + step.StepTypeName())
+	if step.Comment == "fail" {
+		return errors.New("check failed")
+	}
+	return nil
+}
+
+func (e *recordingStepExecutor) record(entry string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.executed = append(e.executed, entry)
+}
+
+func writeScenarioWithExternalSteps(t *testing.T, lastComment string) string {
+	dir := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "sub"), os.ModePerm))
+	require.Nil(t, ioutil.WriteFile(
+		filepath.Join(dir, "main.scen.json"),
+		[]byte(`{
+			"name": "main",
+			"steps": [
+				{ "step": "externalSteps", "path": "sub/init.steps.json" },
+				{ "step": "checkState", "comment": "`+lastComment+`", "accounts": {} }
+			]
+		}`),
+		0644))
+	require.Nil(t, ioutil.WriteFile(
+		filepath.Join(dir, "sub", "init.steps.json"),
+		[]byte(`{
+			"name": "init",
+			"steps": [
+				{ "step": "setState" }
+			]
+		}`),
+		0644))
+	return dir
+}
+
+func TestScenarioStepHooks(t *testing.T) {
+	dir := writeScenarioWithExternalSteps(t, "fail")
+
+	executor := &recordingStepExecutor{}
+	var hookCalls []string
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	runner.AddStepHook(&StepHookFuncs{
+		Before: func(execCtx *ExecutionContext, step mj.Step) error {
+			hookCalls = append(hookCalls, fmt.Sprintf("before %d %s", execCtx.StepIndex, step.StepTypeName()))
+			return nil
+		},
+		After: func(execCtx *ExecutionContext, step mj.Step, stepErr error) error {
+			hookCalls = append(hookCalls, fmt.Sprintf("after %d %s %v", execCtx.StepIndex, step.StepTypeName(), stepErr))
+			return nil
+		},
+	})
+
+	err := runner.RunSingleJSONScenario(filepath.Join(dir, "main.scen.json"))
+	require.NotNil(t, err)
+	require.Equal(t, []string{"main:setState", "main:checkState"}, executor.executed)
+	require.Equal(t, []string{
+		"before 0 setState",
+		"after 0 setState <nil>",
+		"before 1 checkState",
+		"after 1 checkState check failed",
+	}, hookCalls)
+}
+
+func TestScenarioStepSubtests(t *testing.T) {
+	dir := writeScenarioWithExternalSteps(t, "")
+
+	executor := &recordingStepExecutor{}
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	runner.RunAllJSONScenariosAsSubtests(t, dir, "", ".scen.json", nil, SubtestOptions{
+		StepSubtests: true,
+	})
+
+	require.Equal(t, []string{"main:setState", "main:checkState"}, executor.executed)
+}
+
+func TestScenarioParallelSubtests(t *testing.T) {
+	dir := writeDummyScenarios(t, "a_ok", "b_ok", "c_ok")
+
+	executor := &dummyExecutor{}
+	t.Run("Scenarios", func(t *testing.T) {
+		runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+		runner.RunAllJSONScenariosAsSubtests(t, dir, "", ".scen.json", []string{"c_*"}, SubtestOptions{
+			Parallel: true,
+			NewRunner: func() *ScenarioRunner {
+				return NewScenarioRunner(executor, NewDefaultFileResolver())
+			},
+		})
+	})
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&executor.nrExecuted))
+}
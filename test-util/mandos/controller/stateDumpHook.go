@@ -0,0 +1,74 @@
+package mandoscontroller
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	worldmock "github.com/kalyan3104/dme-vm-util/mock-hook-blockchain"
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjwrite "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/write"
+)
+
+// StateDumpFileSuffix is the suffix of the files produced by the StateDumpHook.
+// It is deliberately different from ".scen.json", so dumps never get picked up as scenarios by the directory runners.
+const StateDumpFileSuffix = ".state.json"
+
+// StateDumpHook is a step hook that saves the world state after each step,
+// as a scenario file containing a single setState step.
+// Dumps go to <DumpDir>/<scenario name>/<step index>_<step type>.state.json,
+// so consecutive states can be diffed, and any of them can be loaded as external steps
+// to resume a scenario from that point.
+type StateDumpHook struct {
+	DumpDir  string
+	GetState func() *mj.SetStateStep
+}
+
+var _ StepHook = (*StateDumpHook)(nil)
+
+// NewMockStateDumpHook creates a StateDumpHook that dumps the entire state of a blockchain mock.
+func NewMockStateDumpHook(dumpDir string, world *worldmock.BlockchainHookMock) *StateDumpHook {
+	return &StateDumpHook{
+		DumpDir:  dumpDir,
+		GetState: world.ExportSetStateStep,
+	}
+}
+
+// BeforeStep does nothing.
+func (h *StateDumpHook) BeforeStep(_ *ExecutionContext, _ mj.Step) error {
+	return nil
+}
+
+// AfterStep saves the state, regardless of whether the step failed or not.
+func (h *StateDumpHook) AfterStep(execCtx *ExecutionContext, step mj.Step, stepErr error) error {
+	stateStep := h.GetState()
+	stateStep.Comment = fmt.Sprintf("state after step %s", stepName(execCtx.StepIndex, step))
+	if stepErr != nil {
+		stateStep.Comment += " (failed)"
+	}
+	dumpScenario := &mj.Scenario{
+		Name:     execCtx.Scenario.Name,
+		CheckGas: true,
+		Steps:    []mj.Step{stateStep},
+	}
+
+	dumpPath := filepath.Join(
+		h.DumpDir,
+		scenarioBaseName(execCtx.ScenarioPath),
+		stepName(execCtx.StepIndex, step)+StateDumpFileSuffix)
+	err := os.MkdirAll(filepath.Dir(dumpPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dumpPath, []byte(mjwrite.ScenarioToJSONString(dumpScenario)), 0644)
+}
+
+// scenarioBaseName yields the scenario file name, without the json extensions.
+func scenarioBaseName(scenarioPath string) string {
+	name := filepath.Base(scenarioPath)
+	name = strings.TrimSuffix(name, ".json")
+	name = strings.TrimSuffix(name, ".scen")
+	return name
+}
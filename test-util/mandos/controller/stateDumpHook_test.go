@@ -0,0 +1,74 @@
+package mandoscontroller
+
+import (
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	worldmock "github.com/kalyan3104/dme-vm-util/mock-hook-blockchain"
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+	"github.com/stretchr/testify/require"
+)
+
+// balanceStepExecutor increments the balance of an account for each step.
+type balanceStepExecutor struct {
+	world   *worldmock.BlockchainHookMock
+	address []byte
+}
+
+func (e *balanceStepExecutor) Reset() {}
+
+func (e *balanceStepExecutor) ExecuteScenario(_ *mj.Scenario, _ mjparse.FileResolver) error {
+	return nil
+}
+
+func (e *balanceStepExecutor) ExecuteSetState(_ *ExecutionContext, _ *mj.SetStateStep) error {
+	return e.world.UpdateBalanceWithDelta(e.address, big.NewInt(1))
+}
+
+func (e *balanceStepExecutor) ExecuteTx(_ *ExecutionContext, _ *mj.TxStep) error {
+	return e.world.UpdateBalanceWithDelta(e.address, big.NewInt(1))
+}
+
+func (e *balanceStepExecutor) ExecuteCheckState(_ *ExecutionContext, _ *mj.CheckStateStep) error {
+	return e.world.UpdateBalanceWithDelta(e.address, big.NewInt(1))
+}
+
+func TestStateDumpHook(t *testing.T) {
+	scenarioDir := writeScenarioWithExternalSteps(t, "")
+	dumpDir := t.TempDir()
+
+	address := []byte("account_________________________")
+	world := worldmock.NewMock()
+	world.AcctMap.PutAccount(&worldmock.Account{
+		Address: address,
+		Nonce:   5,
+		Balance: big.NewInt(100),
+		Storage: map[string][]byte{"key": []byte("value")},
+	})
+
+	executor := &balanceStepExecutor{world: world, address: address}
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	runner.AddStepHook(NewMockStateDumpHook(dumpDir, world))
+	err := runner.RunSingleJSONScenario(filepath.Join(scenarioDir, "main.scen.json"))
+	require.Nil(t, err)
+
+	for stepIndex, dumpFile := range []string{"000_setState.state.json", "001_checkState.state.json"} {
+		contents, err := ioutil.ReadFile(filepath.Join(dumpDir, "main", dumpFile))
+		require.Nil(t, err)
+
+		p := mjparse.Parser{}
+		dump, err := p.ParseScenarioFile(contents)
+		require.Nil(t, err)
+		require.Len(t, dump.Steps, 1)
+		setState := dump.Steps[0].(*mj.SetStateStep)
+		require.Len(t, setState.Accounts, 1)
+		require.Equal(t, address, setState.Accounts[0].Address.Value)
+		require.Equal(t, uint64(5), setState.Accounts[0].Nonce.Value)
+		require.Equal(t, big.NewInt(int64(101+stepIndex)), setState.Accounts[0].Balance.Value)
+		require.Equal(t, []byte("key"), setState.Accounts[0].Storage[0].Key.Value)
+		require.Equal(t, []byte("value"), setState.Accounts[0].Storage[0].Value.Value)
+	}
+}
@@ -0,0 +1,77 @@
+package mandoscontroller
+
+import (
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+)
+
+// ExecutionContext groups the information about the scenario that is currently being executed.
+type ExecutionContext struct {
+	// Scenario is the scenario being executed, it holds scenario-wide settings, such as CheckGas.
+	Scenario *mj.Scenario
+
+	// ScenarioPath is the absolute path of the scenario file.
+	ScenarioPath string
+
+	// FileResolver helps with resolving paths relative to the scenario.
+	FileResolver mjparse.FileResolver
+
+	// StepIndex is the position of the current step, after external steps were expanded.
+	StepIndex int
+}
+
+// StepExecutor is an optional extension of ScenarioExecutor, for executors that can run a scenario one step at a time.
+// When the executor implements it, the runner owns the step loop:
+// it loads external steps, calls the step hooks and dispatches each step to the corresponding method.
+type StepExecutor interface {
+	// ExecuteSetState saves the data in the step to the world state.
+	ExecuteSetState(*ExecutionContext, *mj.SetStateStep) error
+
+	// ExecuteTx executes a transaction and checks its result, if the step has an expected result.
+	ExecuteTx(*ExecutionContext, *mj.TxStep) error
+
+	// ExecuteCheckState verifies the world state. Failure is signaled by returning an error.
+	ExecuteCheckState(*ExecutionContext, *mj.CheckStateStep) error
+}
+
+// NextBlockExecutor is an optional extension of StepExecutor, for executors that can simulate block progression.
+// Scenarios with nextBlock steps fail on executors that do not implement it.
+type NextBlockExecutor interface {
+	// ExecuteNextBlock produces a new block in the world state.
+	ExecuteNextBlock(*ExecutionContext, *mj.NextBlockStep) error
+}
+
+// StepHook gets called around each step executed by a StepExecutor.
+// It is the place for cross-cutting concerns, like tracing, gas profiling or state dumps.
+type StepHook interface {
+	// BeforeStep is called before each step. Returning an error aborts the scenario without executing the step.
+	BeforeStep(*ExecutionContext, mj.Step) error
+
+	// AfterStep is called after each step, including failed ones, in which case stepErr is not nil.
+	// Returning an error causes the step to fail.
+	AfterStep(execCtx *ExecutionContext, step mj.Step, stepErr error) error
+}
+
+// StepHookFuncs adapts plain functions to the StepHook interface. Nil functions are ignored.
+type StepHookFuncs struct {
+	Before func(*ExecutionContext, mj.Step) error
+	After  func(*ExecutionContext, mj.Step, error) error
+}
+
+var _ StepHook = (*StepHookFuncs)(nil)
+
+// BeforeStep calls the Before function, if any.
+func (h *StepHookFuncs) BeforeStep(execCtx *ExecutionContext, step mj.Step) error {
+	if h.Before == nil {
+		return nil
+	}
+	return h.Before(execCtx, step)
+}
+
+// AfterStep calls the After function, if any.
+func (h *StepHookFuncs) AfterStep(execCtx *ExecutionContext, step mj.Step, stepErr error) error {
+	if h.After == nil {
+		return nil
+	}
+	return h.After(execCtx, step, stepErr)
+}
@@ -1,6 +1,7 @@
 package mandoscontroller
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -64,6 +65,107 @@ func (r *TestRunner) RunAllJSONTestsInDirectory(
 	return nil
 }
 
+// RunAllJSONTestsInDirectoryWithContext walks directory, parses and prepares all json tests,
+// then calls testExecutor for each of them, observing the context and the run options.
+// It returns a summary of the run, plus an error if any test failed or timed out, or if the context got cancelled.
+func (r *TestRunner) RunAllJSONTestsInDirectoryWithContext(
+	ctx context.Context,
+	generalTestPath string,
+	specificTestPath string,
+	allowedSuffix string,
+	excludedFilePatterns []string,
+	options RunOptions) (*RunSummary, error) {
+
+	return runAllInDirectoryWithContext(
+		ctx,
+		"Test",
+		generalTestPath,
+		specificTestPath,
+		allowedSuffix,
+		excludedFilePatterns,
+		options,
+		func(ctx context.Context, testFilePath string) error {
+			return r.RunSingleJSONTestWithContext(ctx, testFilePath, options.Timeout)
+		})
+}
+
+func runAllInDirectoryWithContext(
+	ctx context.Context,
+	label string,
+	generalTestPath string,
+	specificTestPath string,
+	allowedSuffix string,
+	excludedFilePatterns []string,
+	options RunOptions,
+	runOne func(ctx context.Context, testFilePath string) error) (*RunSummary, error) {
+
+	mainDirPath := path.Join(generalTestPath, specificTestPath)
+	summary := &RunSummary{}
+
+	err := filepath.Walk(mainDirPath, func(testFilePath string, info os.FileInfo, err error) error {
+		if !strings.HasSuffix(testFilePath, allowedSuffix) {
+			return nil
+		}
+		if ctx.Err() != nil || summary.shouldStop(options) {
+			summary.Interrupted = true
+			return errStopWalk
+		}
+
+		shortPath := shortenTestPath(testFilePath, generalTestPath)
+		fmt.Printf("%s: %s ... ", label, shortPath)
+		if isExcluded(excludedFilePatterns, testFilePath, generalTestPath) {
+			summary.Skipped = append(summary.Skipped, shortPath)
+			fmt.Print("  skip\n")
+			return nil
+		}
+
+		testErr := runOne(ctx, testFilePath)
+		nrRetries := 0
+		for ; nrRetries < options.Retries && shouldRetry(ctx, testErr); nrRetries++ {
+			fmt.Print("  retrying ... ")
+			testErr = runOne(ctx, testFilePath)
+		}
+		switch {
+		case testErr == nil:
+			summary.Passed = append(summary.Passed, shortPath)
+			if nrRetries > 0 {
+				summary.Retried = append(summary.Retried, shortPath)
+			}
+			fmt.Print("  ok\n")
+		case errors.Is(testErr, ErrScenarioAbandoned):
+			// it still uses the executor, so no other file can run safely
+			summary.TimedOut = append(summary.TimedOut, shortPath)
+			summary.Interrupted = true
+			fmt.Print("  TIMEOUT!!! (still running, stopping the run)\n")
+			return errStopWalk
+		case errors.Is(testErr, ErrScenarioTimeout):
+			summary.TimedOut = append(summary.TimedOut, shortPath)
+			fmt.Print("  TIMEOUT!!!\n")
+		case ctx.Err() != nil:
+			// cancelled mid-file, it counts neither as passed nor as failed
+			summary.Interrupted = true
+			fmt.Print("  cancelled\n")
+			return errStopWalk
+		default:
+			summary.Failed = append(summary.Failed, shortPath)
+			fmt.Print("  FAIL!!!\n")
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopWalk) {
+		return summary, err
+	}
+	fmt.Printf("Done. %s\n", summary.String())
+	if ctx.Err() != nil {
+		return summary, ctx.Err()
+	}
+	if summary.NrFailures() > 0 {
+		return summary, errors.New("Some tests failed")
+	}
+
+	return summary, nil
+}
+
 func shortenTestPath(path string, generalTestPath string) string {
 	if strings.HasPrefix(path, generalTestPath+"/") {
 		return path[len(generalTestPath)+1:]
@@ -1,10 +1,12 @@
 package mandoscontroller
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
 	mjwrite "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/write"
@@ -12,10 +14,60 @@ import (
 
 // RunSingleJSONTest parses and prepares test, then calls testCallback.
 func (r *TestRunner) RunSingleJSONTest(contextPath string) error {
+	top, err := r.parseTestFile(contextPath)
+	if err != nil {
+		return err
+	}
+
+	for _, test := range top {
+		testErr := r.Executor.ExecuteTest(test)
+		if testErr != nil {
+			return testErr
+		}
+	}
+
+	return nil
+}
+
+// RunSingleJSONTestWithContext parses and prepares the test file, then executes all tests in it.
+// It returns early if the context is done, or with ErrScenarioTimeout if the timeout expires (0 means no timeout).
+// If it returns ErrScenarioAbandoned, the executor is still in use and the runner must not be used again.
+// The timeout applies to the whole file.
+func (r *TestRunner) RunSingleJSONTestWithContext(
+	ctx context.Context,
+	contextPath string,
+	timeout time.Duration) error {
+
+	top, err := r.parseTestFile(contextPath)
+	if err != nil {
+		return err
+	}
+
+	return runWithTimeout(ctx, timeout, func(ctx context.Context) error {
+		ctxExecutor, hasContext := r.Executor.(TestExecutorWithContext)
+		for _, test := range top {
+			var testErr error
+			if hasContext {
+				testErr = ctxExecutor.ExecuteTestWithContext(ctx, test)
+			} else {
+				testErr = r.Executor.ExecuteTest(test)
+			}
+			if testErr != nil {
+				return testErr
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+func (r *TestRunner) parseTestFile(contextPath string) ([]*mj.Test, error) {
 	var err error
 	contextPath, err = filepath.Abs(contextPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Open our jsonFile
@@ -23,7 +75,7 @@ func (r *TestRunner) RunSingleJSONTest(contextPath string) error {
 	jsonFile, err = os.Open(contextPath)
 	// if we os.Open returns an error then handle it
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// defer the closing of our jsonFile so that we can parse it later on
@@ -31,23 +83,14 @@ func (r *TestRunner) RunSingleJSONTest(contextPath string) error {
 
 	byteValue, err := ioutil.ReadAll(jsonFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	r.Parser.FileResolver.SetContext(contextPath)
-	top, parseErr := r.Parser.ParseTestFile(byteValue)
-	if parseErr != nil {
-		return parseErr
-	}
-
-	for _, test := range top {
-		testErr := r.Executor.ExecuteTest(test)
-		if testErr != nil {
-			return testErr
-		}
-	}
-
-	return nil
+	r.Parser.StartFile(contextPath)
+	top, err := r.Parser.ParseTestFile(byteValue)
+	printParseWarnings(r.Parser.Warnings)
+	return top, err
 }
 
 // tool to convert .test.json -> .scen.json
@@ -1,6 +1,8 @@
 package mandoscontroller
 
 import (
+	"context"
+
 	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
 	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
 )
@@ -11,6 +13,15 @@ type TestExecutor interface {
 	ExecuteTest(*mj.Test) error
 }
 
+// TestExecutorWithContext is an optional extension of TestExecutor.
+// Executors implementing it get notified when a test times out or the run gets cancelled,
+// otherwise they are simply abandoned.
+type TestExecutorWithContext interface {
+	// ExecuteTestWithContext executes the test, just like ExecuteTest,
+	// but should return as soon as possible once the context is done.
+	ExecuteTestWithContext(context.Context, *mj.Test) error
+}
+
 // TestRunner is a component that can run tests, using a provided executor.
 type TestRunner struct {
 	Executor TestExecutor
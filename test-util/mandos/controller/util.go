@@ -1,6 +1,11 @@
 package mandoscontroller
 
-import mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+import (
+	"fmt"
+	"os"
+
+	mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
+)
 
 // NewDefaultFileResolver yields a new DefaultFileResolver instance.
 // Reexported here to avoid having all external packages importing the parser.
@@ -8,3 +13,11 @@ import mjparse "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/parse"
 func NewDefaultFileResolver() *mjparse.DefaultFileResolver {
 	return mjparse.NewDefaultFileResolver()
 }
+
+// printParseWarnings lists the problems the parser tolerated.
+// They go to stderr, so they do not break the progress lines of the directory runs.
+func printParseWarnings(warnings []*mjparse.ParseWarning) {
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning.Error())
+	}
+}
@@ -3,14 +3,20 @@ package mandosjsonmodel
 import "bytes"
 
 // Account is a json object representing an account.
+// Owner, Username, CodeMetadata, Shard and DeveloperRewards are optional, they have an empty Original when missing.
 type Account struct {
-	Address       JSONBytes
-	Comment       string
-	Nonce         JSONUint64
-	Balance       JSONBigInt
-	Storage       []*StorageKeyValuePair
-	Code          JSONBytes
-	AsyncCallData string
+	Address          JSONBytes
+	Comment          string
+	Nonce            JSONUint64
+	Balance          JSONBigInt
+	Storage          []*StorageKeyValuePair
+	Code             JSONBytes
+	AsyncCallData    string
+	Owner            JSONBytes
+	Username         JSONBytes
+	CodeMetadata     JSONBytes
+	Shard            JSONUint64
+	DeveloperRewards JSONBigInt
 }
 
 // StorageKeyValuePair is a json key value pair in the storage map.
@@ -20,15 +26,33 @@ type StorageKeyValuePair struct {
 }
 
 // CheckAccount is a json object representing checks for an account.
+// Owner, Username, CodeMetadata, Shard and DeveloperRewards are not checked when missing:
+// they are "*", with an empty Original.
 type CheckAccount struct {
-	Address       JSONBytes
-	Comment       string
-	Nonce         JSONCheckUint64
-	Balance       JSONCheckBigInt
-	IgnoreStorage bool
-	CheckStorage  []*StorageKeyValuePair
-	Code          JSONCheckBytes
-	AsyncCallData string
+	Address          JSONBytes
+	Comment          string
+	Nonce            JSONCheckUint64
+	Balance          JSONCheckBigInt
+	IgnoreStorage    bool
+	CheckStorage     []*StorageKeyValuePair
+	Code             JSONCheckBytes
+	AsyncCallData    string
+	Owner            JSONCheckBytes
+	Username         JSONCheckBytes
+	CodeMetadata     JSONCheckBytes
+	Shard            JSONCheckUint64
+	DeveloperRewards JSONCheckBigInt
+}
+
+// NewCheckAccount creates a CheckAccount where the optional fields are not checked.
+func NewCheckAccount() *CheckAccount {
+	return &CheckAccount{
+		Owner:            JSONCheckBytes{IsStar: true},
+		Username:         JSONCheckBytes{IsStar: true},
+		CodeMetadata:     JSONCheckBytes{IsStar: true},
+		Shard:            JSONCheckUint64{IsStar: true},
+		DeveloperRewards: JSONCheckBigInt{IsStar: true},
+	}
 }
 
 // CheckAccounts encodes rules to check mock accounts.
@@ -0,0 +1,32 @@
+package mandosjsonmodel
+
+// CheckAsyncCall is a json object representing checks for an asynchronous call recorded by the blockchain mock.
+// Fields missing from the json are not checked: the value fields are "*" and the strings are empty.
+type CheckAsyncCall struct {
+	From      JSONCheckBytes
+	To        JSONCheckBytes
+	Function  string
+	Arguments []JSONCheckBytes
+	Value     JSONCheckBigInt
+	GasLimit  JSONCheckUint64
+	Callback  string
+
+	// Status and Out refer to the result of the call on the destination.
+	// They only make sense for calls that were already executed.
+	Status JSONCheckBigInt
+	Out    []JSONCheckBytes
+
+	// IgnoreArguments and IgnoreOut are set when the corresponding lists are missing.
+	IgnoreArguments bool
+	IgnoreOut       bool
+}
+
+// CheckAsyncCalls encodes rules to check the asynchronous calls recorded by the blockchain mock.
+// The lists must match exactly, in order. Lists missing from the json are not checked.
+type CheckAsyncCalls struct {
+	Pending   []*CheckAsyncCall
+	Completed []*CheckAsyncCall
+
+	IgnorePending   bool
+	IgnoreCompleted bool
+}
@@ -0,0 +1,99 @@
+package mandosjsonmodel
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// CodeMetadataLength is the length of the code metadata, in bytes.
+const CodeMetadataLength = 2
+
+// CodeMetadataFlagSeparator separates flags in the readable code metadata format, e.g. "upgradeable|payable".
+const CodeMetadataFlagSeparator = "|"
+
+type codeMetadataFlag struct {
+	name      string
+	byteIndex int
+	mask      byte
+}
+
+// the order here is also the order in which flags are written
+var codeMetadataFlags = []codeMetadataFlag{
+	{name: "upgradeable", byteIndex: 0, mask: 0x01},
+	{name: "readable", byteIndex: 0, mask: 0x04},
+	{name: "payable", byteIndex: 1, mask: 0x02},
+}
+
+// IsCodeMetadataFlags is true if the string is in the readable code metadata format,
+// i.e. a list of known flag names, separated by "|".
+func IsCodeMetadataFlags(str string) bool {
+	if len(str) == 0 {
+		return false
+	}
+	for _, name := range strings.Split(str, CodeMetadataFlagSeparator) {
+		if findCodeMetadataFlag(name) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// CodeMetadataFromFlags converts a readable code metadata string, e.g. "upgradeable|payable", to its byte representation.
+func CodeMetadataFromFlags(str string) ([]byte, error) {
+	metadata := make([]byte, CodeMetadataLength)
+	for _, name := range strings.Split(str, CodeMetadataFlagSeparator) {
+		flag := findCodeMetadataFlag(name)
+		if flag == nil {
+			return nil, fmt.Errorf("unknown code metadata flag: %s", name)
+		}
+		metadata[flag.byteIndex] |= flag.mask
+	}
+	return metadata, nil
+}
+
+// CodeMetadataToString yields the readable code metadata format of some metadata bytes.
+// It falls back to hex if the metadata contains unknown bits, or if no flag is set.
+func CodeMetadataToString(metadata []byte) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	remaining := make([]byte, len(metadata))
+	copy(remaining, metadata)
+
+	var names []string
+	if len(metadata) == CodeMetadataLength {
+		for _, flag := range codeMetadataFlags {
+			if remaining[flag.byteIndex]&flag.mask != 0 {
+				names = append(names, flag.name)
+				remaining[flag.byteIndex] &^= flag.mask
+			}
+		}
+	}
+	for _, b := range remaining {
+		if b != 0 {
+			return "0x" + hex.EncodeToString(metadata)
+		}
+	}
+	if len(names) == 0 {
+		return "0x" + hex.EncodeToString(metadata)
+	}
+	return strings.Join(names, CodeMetadataFlagSeparator)
+}
+
+// JSONCodeMetadataFromBytes creates a JSONBytes holding code metadata, with the original string in the readable format.
+func JSONCodeMetadataFromBytes(metadata []byte) JSONBytes {
+	return JSONBytes{
+		Value:    metadata,
+		Original: CodeMetadataToString(metadata),
+	}
+}
+
+func findCodeMetadataFlag(name string) *codeMetadataFlag {
+	for i := range codeMetadataFlags {
+		if codeMetadataFlags[i].name == name {
+			return &codeMetadataFlags[i]
+		}
+	}
+	return nil
+}
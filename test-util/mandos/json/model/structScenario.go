@@ -22,10 +22,17 @@ type NewAddressMock struct {
 
 // BlockInfo contains data for the block info hooks
 type BlockInfo struct {
-	BlockTimestamp JSONUint64
-	BlockNonce     JSONUint64
-	BlockRound     JSONUint64
-	BlockEpoch     JSONUint64
+	BlockTimestamp  JSONUint64
+	BlockNonce      JSONUint64
+	BlockRound      JSONUint64
+	BlockEpoch      JSONUint64
+	BlockRandomSeed JSONBytes
+}
+
+// BlockHash maps a block nonce to the hash of that block.
+type BlockHash struct {
+	Nonce JSONUint64
+	Hash  JSONBytes
 }
 
 // ExternalStepsStep allows including steps from another file
@@ -39,14 +46,28 @@ type SetStateStep struct {
 	Accounts          []*Account
 	PreviousBlockInfo *BlockInfo
 	CurrentBlockInfo  *BlockInfo
-	BlockHashes       []JSONBytes
 	NewAddressMocks   []*NewAddressMock
+
+	// BlockHashes is the list form of the block hashes, the hash at index i is the hash of the block with nonce i.
+	BlockHashes []JSONBytes
+
+	// BlockHashesByNonce is the map form of the block hashes, it specifies the block nonces explicitly.
+	BlockHashesByNonce []*BlockHash
 }
 
 // CheckStateStep is a step where the state of the blockchain mock is verified.
 type CheckStateStep struct {
-	Comment       string
-	CheckAccounts *CheckAccounts
+	Comment         string
+	CheckAccounts   *CheckAccounts
+	CheckAsyncCalls *CheckAsyncCalls
+}
+
+// NextBlockStep is a step where the blockchain mock produces a new block.
+// Rounds and Seconds are optional, they have an empty Original when missing.
+type NextBlockStep struct {
+	Comment string
+	Rounds  JSONUint64
+	Seconds JSONUint64
 }
 
 // TxStep is a step where a transaction is executed.
@@ -60,6 +81,7 @@ type TxStep struct {
 var _ Step = (*ExternalStepsStep)(nil)
 var _ Step = (*SetStateStep)(nil)
 var _ Step = (*CheckStateStep)(nil)
+var _ Step = (*NextBlockStep)(nil)
 var _ Step = (*TxStep)(nil)
 
 // StepNameExternalSteps is a json step type name.
@@ -86,6 +108,14 @@ func (*CheckStateStep) StepTypeName() string {
 	return StepNameCheckState
 }
 
+// StepNameNextBlock is a json step type name.
+const StepNameNextBlock = "nextBlock"
+
+// StepTypeName type as string
+func (*NextBlockStep) StepTypeName() string {
+	return StepNameNextBlock
+}
+
 // StepNameScCall is a json step type name.
 const StepNameScCall = "scCall"
 
@@ -1,7 +1,9 @@
 package mandosjsonmodel
 
 import (
+	"encoding/hex"
 	"math/big"
+	"strconv"
 )
 
 // JSONBytes stores the parsed byte slice value but also the original parsed string
@@ -21,3 +23,34 @@ type JSONUint64 struct {
 	Value    uint64
 	Original string
 }
+
+// JSONBytesFromBytes creates a JSONBytes, with the original string generated from the value, in hex.
+func JSONBytesFromBytes(value []byte) JSONBytes {
+	original := ""
+	if len(value) > 0 {
+		original = "0x" + hex.EncodeToString(value)
+	}
+	return JSONBytes{
+		Value:    value,
+		Original: original,
+	}
+}
+
+// JSONBigIntFromBigInt creates a JSONBigInt, with the original string generated from the value, in base 10.
+func JSONBigIntFromBigInt(value *big.Int) JSONBigInt {
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return JSONBigInt{
+		Value:    value,
+		Original: value.String(),
+	}
+}
+
+// JSONUint64FromUint64 creates a JSONUint64, with the original string generated from the value, in base 10.
+func JSONUint64FromUint64(value uint64) JSONUint64 {
+	return JSONUint64{
+		Value:    value,
+		Original: strconv.FormatUint(value, 10),
+	}
+}
@@ -0,0 +1,51 @@
+package mandosjsonparse
+
+import (
+	mhc "github.com/kalyan3104/dme-vm-util/mock-hook-crypto"
+)
+
+// HashProvider computes the hashes available in value expressions, e.g. "keccak256:...".
+type HashProvider interface {
+	// Sha256 cryptographic function
+	Sha256(data []byte) ([]byte, error)
+
+	// Keccak256 cryptographic function
+	Keccak256(data []byte) ([]byte, error)
+
+	// Ripemd160 cryptographic function
+	Ripemd160(data []byte) ([]byte, error)
+
+	// Blake2b cryptographic function, with a 32 byte output
+	Blake2b(data []byte) ([]byte, error)
+
+	// Sha3 cryptographic function, SHA3-256
+	Sha3(data []byte) ([]byte, error)
+}
+
+var _ HashProvider = mhc.KryptoHookMockInstance
+
+// hashFunction yields the hash function with the given name, as used in value expression prefixes.
+func (p *Parser) hashFunction(name string) (func(data []byte) ([]byte, error), bool) {
+	hashProvider := p.getHashProvider()
+	switch name {
+	case "keccak256":
+		return hashProvider.Keccak256, true
+	case "sha256":
+		return hashProvider.Sha256, true
+	case "ripemd160":
+		return hashProvider.Ripemd160, true
+	case "blake2b":
+		return hashProvider.Blake2b, true
+	case "sha3":
+		return hashProvider.Sha3, true
+	default:
+		return nil, false
+	}
+}
+
+func (p *Parser) getHashProvider() HashProvider {
+	if p.HashProvider == nil {
+		return mhc.KryptoHookMockInstance
+	}
+	return p.HashProvider
+}
@@ -50,11 +50,16 @@ func (p *Parser) processAccount(acctRaw oj.OJsonObject) (*mj.Account, error) {
 			if !storageOk {
 				return nil, errors.New("invalid account storage")
 			}
+			storageKeys := newDuplicateChecker("storage key")
 			for _, storageKvp := range storageMap.OrderedKV {
 				byteKey, err := p.parseAnyValueAsByteArray(storageKvp.Key)
 				if err != nil {
 					return nil, fmt.Errorf("invalid account storage key: %w", err)
 				}
+				err = p.checkDuplicate(storageKeys, byteKey, storageKvp.Key)
+				if err != nil {
+					return nil, err
+				}
 				byteVal, err := p.processAnyValueAsByteArray(storageKvp.Value)
 				if err != nil {
 					return nil, fmt.Errorf("invalid account storage value: %w", err)
@@ -75,6 +80,31 @@ func (p *Parser) processAccount(acctRaw oj.OJsonObject) (*mj.Account, error) {
 			if err != nil {
 				return nil, fmt.Errorf("invalid asyncCallData string: %w", err)
 			}
+		case "owner":
+			acct.Owner, err = p.processAnyValueAsByteArray(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid account owner: %w", err)
+			}
+		case "username":
+			acct.Username, err = p.processAnyValueAsByteArray(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid account username: %w", err)
+			}
+		case "codeMetadata":
+			acct.CodeMetadata, err = p.processCodeMetadata(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid account code metadata: %w", err)
+			}
+		case "shard":
+			acct.Shard, err = p.processUint64(kvp.Value)
+			if err != nil {
+				return nil, errors.New("invalid account shard")
+			}
+		case "developerRewards":
+			acct.DeveloperRewards, err = p.processBigInt(kvp.Value, bigIntUnsignedBytes)
+			if err != nil {
+				return nil, errors.New("invalid account developer rewards")
+			}
 		default:
 			return nil, fmt.Errorf("unknown account field: %s", kvp.Key)
 		}
@@ -89,6 +119,7 @@ func (p *Parser) processAccountMap(acctMapRaw oj.OJsonObject) ([]*mj.Account, er
 	if !isPreMap {
 		return nil, errors.New("unmarshalled account map object is not a map")
 	}
+	addresses := newDuplicateChecker("account address")
 	for _, acctKVP := range preMap.OrderedKV {
 		acct, acctErr := p.processAccount(acctKVP.Value)
 		if acctErr != nil {
@@ -98,6 +129,10 @@ func (p *Parser) processAccountMap(acctMapRaw oj.OJsonObject) ([]*mj.Account, er
 		if hexErr != nil {
 			return nil, hexErr
 		}
+		dupErr := p.checkDuplicate(addresses, acctAddr.Value, acctAddr.Original)
+		if dupErr != nil {
+			return nil, dupErr
+		}
 		acct.Address = acctAddr
 		accounts = append(accounts, acct)
 
@@ -111,9 +146,7 @@ func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*mj.CheckAccount,
 		return nil, errors.New("unmarshalled account object is not a map")
 	}
 
-	acct := mj.CheckAccount{
-		IgnoreStorage: false,
-	}
+	acct := mj.NewCheckAccount()
 	var err error
 
 	for _, kvp := range acctMap.OrderedKV {
@@ -142,11 +175,16 @@ func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*mj.CheckAccount,
 				if !storageOk {
 					return nil, errors.New("invalid account storage")
 				}
+				storageKeys := newDuplicateChecker("storage key")
 				for _, storageKvp := range storageMap.OrderedKV {
 					byteKey, err := p.parseAnyValueAsByteArray(storageKvp.Key)
 					if err != nil {
 						return nil, fmt.Errorf("invalid account storage key: %w", err)
 					}
+					err = p.checkDuplicate(storageKeys, byteKey, storageKvp.Key)
+					if err != nil {
+						return nil, err
+					}
 					byteVal, err := p.processAnyValueAsByteArray(storageKvp.Value)
 					if err != nil {
 						return nil, fmt.Errorf("invalid account storage value: %w", err)
@@ -169,12 +207,37 @@ func (p *Parser) processCheckAccount(acctRaw oj.OJsonObject) (*mj.CheckAccount,
 			if err != nil {
 				return nil, fmt.Errorf("invalid asyncCallData string: %w", err)
 			}
+		case "owner":
+			acct.Owner, err = p.parseCheckBytes(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid account owner: %w", err)
+			}
+		case "username":
+			acct.Username, err = p.parseCheckBytes(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid account username: %w", err)
+			}
+		case "codeMetadata":
+			acct.CodeMetadata, err = p.processCheckCodeMetadata(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid account code metadata: %w", err)
+			}
+		case "shard":
+			acct.Shard, err = p.processCheckUint64(kvp.Value)
+			if err != nil {
+				return nil, errors.New("invalid account shard")
+			}
+		case "developerRewards":
+			acct.DeveloperRewards, err = p.processCheckBigInt(kvp.Value, bigIntUnsignedBytes)
+			if err != nil {
+				return nil, errors.New("invalid account developer rewards")
+			}
 		default:
 			return nil, fmt.Errorf("unknown account field: %s", kvp.Key)
 		}
 	}
 
-	return &acct, nil
+	return acct, nil
 }
 
 func (p *Parser) processCheckAccountMap(acctMapRaw oj.OJsonObject) (*mj.CheckAccounts, error) {
@@ -187,6 +250,7 @@ func (p *Parser) processCheckAccountMap(acctMapRaw oj.OJsonObject) (*mj.CheckAcc
 	if !isPreMap {
 		return nil, errors.New("unmarshalled check account map object is not a map")
 	}
+	addresses := newDuplicateChecker("account address")
 	for _, acctKVP := range preMap.OrderedKV {
 		if acctKVP.Key == "+" {
 			checkAccounts.OtherAccountsAllowed = true
@@ -199,6 +263,10 @@ func (p *Parser) processCheckAccountMap(acctMapRaw oj.OJsonObject) (*mj.CheckAcc
 			if hexErr != nil {
 				return nil, hexErr
 			}
+			dupErr := p.checkDuplicate(addresses, acctAddr.Value, acctAddr.Original)
+			if dupErr != nil {
+				return nil, dupErr
+			}
 			acct.Address = acctAddr
 			checkAccounts.Accounts = append(checkAccounts.Accounts, acct)
 		}
@@ -13,7 +13,6 @@ import (
 )
 
 const filePrefix = "file:"
-const keccak256Prefix = "keccak256:"
 
 func (p *Parser) parseCheckBytes(obj oj.OJsonObject) (mj.JSONCheckBytes, error) {
 	if IsStar(obj) {
@@ -65,18 +64,29 @@ func (p *Parser) parseAnyValueAsByteArray(strRaw string) ([]byte, error) {
 		return fileContents, nil
 	}
 
-	// keccak256
+	// hashes, e.g. "keccak256:..."
 	// TODO: make this part of a proper parser
-	if strings.HasPrefix(strRaw, keccak256Prefix) {
-		arg, err := p.parseAnyValueAsByteArray(strRaw[len(keccak256Prefix):])
-		if err != nil {
-			return []byte{}, fmt.Errorf("cannot parse keccak256 argument: %w", err)
-		}
-		hash, err := keccak256(arg)
-		if err != nil {
-			return []byte{}, fmt.Errorf("error computing keccak256: %w", err)
+	if hashName, arg, found := cutString(strRaw, ":"); found {
+		if hashFunc, isHash := p.hashFunction(hashName); isHash {
+			argBytes, err := p.parseAnyValueAsByteArray(arg)
+			if err != nil {
+				return []byte{}, fmt.Errorf("cannot parse %s argument: %w", hashName, err)
+			}
+			hash, err := hashFunc(argBytes)
+			if err != nil {
+				return []byte{}, fmt.Errorf("error computing %s: %w", hashName, err)
+			}
+			return hash, nil
 		}
-		return hash, nil
+	}
+
+	// signatures and public keys, with deterministic keys derived from names
+	// TODO: make this part of a proper parser
+	if strings.HasPrefix(strRaw, signPrefix) {
+		return p.parseSignExpression(strRaw[len(signPrefix):])
+	}
+	if strings.HasPrefix(strRaw, pubkeyPrefix) {
+		return parsePubkeyExpression(strRaw[len(pubkeyPrefix):])
 	}
 
 	// concatenate values of different formats
@@ -1,9 +1,11 @@
 package mandosjsonparse
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"testing"
 
+	mhc "github.com/kalyan3104/dme-vm-util/mock-hook-crypto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -145,42 +147,42 @@ func TestKeccak256(t *testing.T) {
 	p := Parser{}
 	result, err := p.parseAnyValueAsByteArray("keccak256:0x01|5")
 	require.Nil(t, err)
-	expected, _ := keccak256([]byte{0x01, 0x05})
+	expected, _ := mhc.KryptoHookMockInstance.Keccak256([]byte{0x01, 0x05})
 	require.Equal(t, expected, result)
 
 	result, err = p.parseAnyValueAsByteArray("keccak256:|||0x01|5||||")
 	require.Nil(t, err)
-	expected, _ = keccak256([]byte{0x01, 0x05})
+	expected, _ = mhc.KryptoHookMockInstance.Keccak256([]byte{0x01, 0x05})
 	require.Equal(t, expected, result)
 
 	result, err = p.parseAnyValueAsByteArray("keccak256:|")
 	require.Nil(t, err)
-	expected, _ = keccak256([]byte{})
+	expected, _ = mhc.KryptoHookMockInstance.Keccak256([]byte{})
 	require.Equal(t, expected, result)
 
 	result, err = p.parseAnyValueAsByteArray("keccak256:|||||||")
 	require.Nil(t, err)
-	expected, _ = keccak256([]byte{})
+	expected, _ = mhc.KryptoHookMockInstance.Keccak256([]byte{})
 	require.Equal(t, expected, result)
 
 	result, err = p.parseAnyValueAsByteArray("keccak256:|0")
 	require.Nil(t, err)
-	expected, _ = keccak256([]byte{})
+	expected, _ = mhc.KryptoHookMockInstance.Keccak256([]byte{})
 	require.Equal(t, expected, result)
 
 	result, err = p.parseAnyValueAsByteArray("keccak256:``a|``b")
 	require.Nil(t, err)
-	expected, _ = keccak256([]byte("ab"))
+	expected, _ = mhc.KryptoHookMockInstance.Keccak256([]byte("ab"))
 	require.Equal(t, expected, result)
 
 	result, err = p.parseAnyValueAsByteArray("keccak256:``a|0x62")
 	require.Nil(t, err)
-	expected, _ = keccak256([]byte("ab"))
+	expected, _ = mhc.KryptoHookMockInstance.Keccak256([]byte("ab"))
 	require.Equal(t, expected, result)
 
 	result, err = p.parseAnyValueAsByteArray("keccak256:0x61|``b")
 	require.Nil(t, err)
-	expected, _ = keccak256([]byte("ab"))
+	expected, _ = mhc.KryptoHookMockInstance.Keccak256([]byte("ab"))
 	require.Equal(t, expected, result)
 
 	// some values from the old ERC20 tests
@@ -214,3 +216,103 @@ func TestFile(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, []byte("hello!"), result)
 }
+
+func TestOtherHashes(t *testing.T) {
+	p := Parser{}
+	result, err := p.parseAnyValueAsByteArray("sha256:``abc")
+	require.Nil(t, err)
+	expected, _ := hex.DecodeString("ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")
+	require.Equal(t, expected, result)
+
+	result, err = p.parseAnyValueAsByteArray("ripemd160:``abc")
+	require.Nil(t, err)
+	expected, _ = hex.DecodeString("8eb208f7e05d987a9b044a8e98c6b087f15a0bfc")
+	require.Equal(t, expected, result)
+
+	result, err = p.parseAnyValueAsByteArray("blake2b:``abc")
+	require.Nil(t, err)
+	expected, _ = hex.DecodeString("bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319")
+	require.Equal(t, expected, result)
+
+	result, err = p.parseAnyValueAsByteArray("sha3:``abc")
+	require.Nil(t, err)
+	expected, _ = hex.DecodeString("3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532")
+	require.Equal(t, expected, result)
+
+	// nested
+	result, err = p.parseAnyValueAsByteArray("sha256:sha256:``abc")
+	require.Nil(t, err)
+	inner, _ := mhc.KryptoHookMockInstance.Sha256([]byte("abc"))
+	expected, _ = mhc.KryptoHookMockInstance.Sha256(inner)
+	require.Equal(t, expected, result)
+}
+
+type constantHashProvider struct {
+	mhc.KryptoHookMock
+}
+
+func (constantHashProvider) Keccak256(_ []byte) ([]byte, error) {
+	return []byte("hash"), nil
+}
+
+func TestCustomHashProvider(t *testing.T) {
+	p := Parser{HashProvider: constantHashProvider{}}
+	result, err := p.parseAnyValueAsByteArray("keccak256:``abc")
+	require.Nil(t, err)
+	require.Equal(t, []byte("hash"), result)
+
+	result, err = p.parseAnyValueAsByteArray("sha256:``abc")
+	require.Nil(t, err)
+	expected, _ := mhc.KryptoHookMockInstance.Sha256([]byte("abc"))
+	require.Equal(t, expected, result)
+}
+
+func TestSignatures(t *testing.T) {
+	p := Parser{}
+	crypto := mhc.KryptoHookMockInstance
+
+	pubkey, err := p.parseAnyValueAsByteArray("pubkey-ed25519:alice")
+	require.Nil(t, err)
+	_, expectedPubkey := mhc.Ed25519KeyFromSeed("alice")
+	require.Equal(t, expectedPubkey, pubkey)
+
+	sig, err := p.parseAnyValueAsByteArray("sign-ed25519:alice:``hello|0x01")
+	require.Nil(t, err)
+	require.Nil(t, crypto.VerifyEd25519(pubkey, []byte("hello\x01"), sig))
+
+	sig, err = p.parseAnyValueAsByteArray("sign-ed25519-keccak256:alice:``hello")
+	require.Nil(t, err)
+	hash, _ := mhc.KryptoHookMockInstance.Keccak256([]byte("hello"))
+	require.Nil(t, crypto.VerifyEd25519(pubkey, hash, sig))
+
+	sig, err = p.parseAnyValueAsByteArray("sign-ed25519-sha256:alice:``hello")
+	require.Nil(t, err)
+	sha256Hash := sha256.Sum256([]byte("hello"))
+	require.Nil(t, crypto.VerifyEd25519(pubkey, sha256Hash[:], sig))
+
+	pubkey, err = p.parseAnyValueAsByteArray("pubkey-secp256k1:alice")
+	require.Nil(t, err)
+	sig, err = p.parseAnyValueAsByteArray("sign-secp256k1:alice:``hello")
+	require.Nil(t, err)
+	require.Nil(t, crypto.VerifySecp256k1(pubkey, []byte("hello"), sig))
+
+	pubkey, err = p.parseAnyValueAsByteArray("pubkey-bls:alice")
+	require.Nil(t, err)
+	sig, err = p.parseAnyValueAsByteArray("sign-bls:alice:``hello")
+	require.Nil(t, err)
+	require.Nil(t, crypto.VerifyBLS(pubkey, []byte("hello"), sig))
+
+	// as part of a concatenation
+	result, err := p.parseAnyValueAsByteArray("0x01|pubkey-ed25519:alice")
+	require.Nil(t, err)
+	require.Equal(t, append([]byte{0x01}, expectedPubkey...), result)
+
+	_, err = p.parseAnyValueAsByteArray("sign-ed25519:alice")
+	require.NotNil(t, err)
+	_, err = p.parseAnyValueAsByteArray("sign-rsa:alice:``hello")
+	require.NotNil(t, err)
+	_, err = p.parseAnyValueAsByteArray("sign-ed25519-md5:alice:``hello")
+	require.NotNil(t, err)
+	_, err = p.parseAnyValueAsByteArray("pubkey-ed25519:")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,132 @@
+package mandosjsonparse
+
+import (
+	"errors"
+	"fmt"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
+)
+
+func (p *Parser) processCheckAsyncCalls(asyncCallsRaw oj.OJsonObject) (*mj.CheckAsyncCalls, error) {
+	asyncCallsMap, isMap := asyncCallsRaw.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("unmarshalled async calls object is not a map")
+	}
+
+	checkAsyncCalls := &mj.CheckAsyncCalls{
+		IgnorePending:   true,
+		IgnoreCompleted: true,
+	}
+	var err error
+	for _, kvp := range asyncCallsMap.OrderedKV {
+		switch kvp.Key {
+		case "pending":
+			checkAsyncCalls.IgnorePending = false
+			checkAsyncCalls.Pending, err = p.processCheckAsyncCallList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pending async calls: %w", err)
+			}
+		case "completed":
+			checkAsyncCalls.IgnoreCompleted = false
+			checkAsyncCalls.Completed, err = p.processCheckAsyncCallList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid completed async calls: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown async calls field: %s", kvp.Key)
+		}
+	}
+
+	return checkAsyncCalls, nil
+}
+
+func (p *Parser) processCheckAsyncCallList(listRaw oj.OJsonObject) ([]*mj.CheckAsyncCall, error) {
+	asyncCallList, isList := listRaw.(*oj.OJsonList)
+	if !isList {
+		return nil, errors.New("async call list is not a list")
+	}
+	result := make([]*mj.CheckAsyncCall, 0)
+	for _, asyncCallRaw := range asyncCallList.AsList() {
+		checkAsyncCall, err := p.processCheckAsyncCall(asyncCallRaw)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, checkAsyncCall)
+	}
+	return result, nil
+}
+
+func (p *Parser) processCheckAsyncCall(asyncCallRaw oj.OJsonObject) (*mj.CheckAsyncCall, error) {
+	asyncCallMap, isMap := asyncCallRaw.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("unmarshalled async call object is not a map")
+	}
+
+	// missing fields are not checked
+	checkAsyncCall := &mj.CheckAsyncCall{
+		From:            mj.JSONCheckBytes{IsStar: true},
+		To:              mj.JSONCheckBytes{IsStar: true},
+		Value:           mj.JSONCheckBigInt{IsStar: true},
+		GasLimit:        mj.JSONCheckUint64{IsStar: true},
+		Status:          mj.JSONCheckBigInt{IsStar: true},
+		IgnoreArguments: true,
+		IgnoreOut:       true,
+	}
+	var err error
+	for _, kvp := range asyncCallMap.OrderedKV {
+		switch kvp.Key {
+		case "from":
+			checkAsyncCall.From, err = p.parseCheckBytes(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call from: %w", err)
+			}
+		case "to":
+			checkAsyncCall.To, err = p.parseCheckBytes(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call to: %w", err)
+			}
+		case "function":
+			checkAsyncCall.Function, err = p.parseString(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call function: %w", err)
+			}
+		case "arguments":
+			checkAsyncCall.IgnoreArguments = false
+			checkAsyncCall.Arguments, err = p.parseCheckBytesList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call arguments: %w", err)
+			}
+		case "value":
+			checkAsyncCall.Value, err = p.processCheckBigInt(kvp.Value, bigIntUnsignedBytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call value: %w", err)
+			}
+		case "gasLimit":
+			checkAsyncCall.GasLimit, err = p.processCheckUint64(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call gasLimit: %w", err)
+			}
+		case "callback":
+			checkAsyncCall.Callback, err = p.parseString(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call callback: %w", err)
+			}
+		case "status":
+			checkAsyncCall.Status, err = p.processCheckBigInt(kvp.Value, bigIntSignedBytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call status: %w", err)
+			}
+		case "out":
+			checkAsyncCall.IgnoreOut = false
+			checkAsyncCall.Out, err = p.parseCheckBytesList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid async call out: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown async call field: %s", kvp.Key)
+		}
+	}
+
+	return checkAsyncCall, nil
+}
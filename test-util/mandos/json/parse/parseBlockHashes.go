@@ -0,0 +1,33 @@
+package mandosjsonparse
+
+import (
+	"errors"
+	"fmt"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
+)
+
+// processBlockHashesByNonce parses the map form of the block hashes, from block nonce to block hash.
+func (p *Parser) processBlockHashesByNonce(blockHashesRaw oj.OJsonObject) ([]*mj.BlockHash, error) {
+	blockHashesMap, isMap := blockHashesRaw.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("block hashes object is not a map")
+	}
+	var blockHashes []*mj.BlockHash
+	for _, kvp := range blockHashesMap.OrderedKV {
+		nonce, err := p.processUint64(&oj.OJsonString{Value: kvp.Key})
+		if err != nil {
+			return nil, fmt.Errorf("invalid block nonce %s: %w", kvp.Key, err)
+		}
+		hash, err := p.processAnyValueAsByteArray(kvp.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash for block nonce %s: %w", kvp.Key, err)
+		}
+		blockHashes = append(blockHashes, &mj.BlockHash{
+			Nonce: nonce,
+			Hash:  hash,
+		})
+	}
+	return blockHashes, nil
+}
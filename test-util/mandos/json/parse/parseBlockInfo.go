@@ -38,6 +38,11 @@ func (p *Parser) processBlockInfo(blockInfoRaw oj.OJsonObject) (*mj.BlockInfo, e
 			if err != nil {
 				return nil, fmt.Errorf("error parsing blockEpoch: %w", err)
 			}
+		case "blockRandomSeed":
+			blockInfo.BlockRandomSeed, err = p.processAnyValueAsByteArray(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing blockRandomSeed: %w", err)
+			}
 		default:
 			return nil, fmt.Errorf("unknown block info field: %s", kvp.Key)
 		}
@@ -0,0 +1,35 @@
+package mandosjsonparse
+
+import (
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
+)
+
+// processCodeMetadata accepts either readable flags, e.g. "upgradeable|payable", or any byte array value.
+// Only strings made entirely of flag names count as flags, all other values, e.g. concatenations, are byte arrays.
+func (p *Parser) processCodeMetadata(obj oj.OJsonObject) (mj.JSONBytes, error) {
+	strVal, isStr := obj.(*oj.OJsonString)
+	if !isStr || !mj.IsCodeMetadataFlags(strVal.String()) {
+		return p.processAnyValueAsByteArray(obj)
+	}
+	metadata, err := mj.CodeMetadataFromFlags(strVal.String())
+	return mj.JSONBytes{
+		Value:    metadata,
+		Original: strVal.String(),
+	}, err
+}
+
+func (p *Parser) processCheckCodeMetadata(obj oj.OJsonObject) (mj.JSONCheckBytes, error) {
+	if IsStar(obj) {
+		return p.parseCheckBytes(obj)
+	}
+	jb, err := p.processCodeMetadata(obj)
+	if err != nil {
+		return mj.JSONCheckBytes{}, err
+	}
+	return mj.JSONCheckBytes{
+		Value:    jb.Value,
+		IsStar:   false,
+		Original: jb.Original,
+	}, nil
+}
@@ -10,7 +10,7 @@ import (
 
 // ParseScenarioFile converts a scenario json string to scenario object representation
 func (p *Parser) ParseScenarioFile(jsonString []byte) (*mj.Scenario, error) {
-	jobj, err := oj.ParseOrderedJSON(jsonString)
+	jobj, err := p.parseOrderedJSON(jsonString)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func (p *Parser) processScenarioStepList(obj interface{}) ([]mj.Step, error) {
 // ParseScenarioStep parses a single scenario step, instead of an entire file.
 // Handy for tests, where step snippets can be embedded in code.
 func (p *Parser) ParseScenarioStep(jsonSnippet string) (mj.Step, error) {
-	jobj, err := oj.ParseOrderedJSON([]byte(jsonSnippet))
+	jobj, err := p.parseOrderedJSON([]byte(jsonSnippet))
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +146,11 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 					return nil, fmt.Errorf("error parsing currentBlockInfo: %w", err)
 				}
 			case "blockHashes":
-				step.BlockHashes, err = p.parseByteArrayList(kvp.Value)
+				if _, isMap := kvp.Value.(*oj.OJsonMap); isMap {
+					step.BlockHashesByNonce, err = p.processBlockHashesByNonce(kvp.Value)
+				} else {
+					step.BlockHashes, err = p.parseByteArrayList(kvp.Value)
+				}
 				if err != nil {
 					return nil, fmt.Errorf("error parsing block hashes: %w", err)
 				}
@@ -170,11 +174,41 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 				if err != nil {
 					return nil, fmt.Errorf("cannot parse check state step: %w", err)
 				}
+			case "asyncCalls":
+				step.CheckAsyncCalls, err = p.processCheckAsyncCalls(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse check state async calls: %w", err)
+				}
 			default:
 				return nil, fmt.Errorf("invalid check state field: %s", kvp.Key)
 			}
 		}
 		return step, nil
+	case mj.StepNameNextBlock:
+		step := &mj.NextBlockStep{}
+		for _, kvp := range stepMap.OrderedKV {
+			switch kvp.Key {
+			case "step":
+			case "comment":
+				step.Comment, err = p.parseString(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("bad next block step comment: %w", err)
+				}
+			case "rounds":
+				step.Rounds, err = p.processUint64(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("bad next block rounds: %w", err)
+				}
+			case "seconds":
+				step.Seconds, err = p.processUint64(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("bad next block seconds: %w", err)
+				}
+			default:
+				return nil, fmt.Errorf("invalid next block field: %s", kvp.Key)
+			}
+		}
+		return step, nil
 	case mj.StepNameScCall:
 		return p.parseTxStep(mj.ScCall, stepMap)
 	case mj.StepNameScDeploy:
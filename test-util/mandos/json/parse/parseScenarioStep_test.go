@@ -3,6 +3,8 @@ package mandosjsonparse
 import (
 	"testing"
 
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
+	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,3 +53,113 @@ func TestParseScenario(t *testing.T) {
 	require.NotNil(t, step)
 	require.Equal(t, "scCall", step.StepTypeName())
 }
+
+func TestParseAccountMetadata(t *testing.T) {
+	snippet := `
+	{
+		"step": "checkState",
+		"accounts": {
+			"''contract________________________": {
+				"nonce": "0",
+				"balance": "*",
+				"storage": "*",
+				"code": "*",
+				"owner": "''owner___________________________",
+				"codeMetadata": "readable|payable",
+				"developerRewards": "*"
+			}
+		}
+	}`
+
+	p := Parser{}
+	step, parseErr := p.ParseScenarioStep(snippet)
+	require.Nil(t, parseErr)
+	checkAccount := step.(*mj.CheckStateStep).CheckAccounts.Accounts[0]
+	require.Equal(t, []byte("owner___________________________"), checkAccount.Owner.Value)
+	require.Equal(t, []byte{0x04, 0x02}, checkAccount.CodeMetadata.Value)
+	require.True(t, checkAccount.DeveloperRewards.IsStar)
+	require.True(t, checkAccount.Username.IsStar)
+	require.True(t, checkAccount.Shard.IsStar)
+	require.Equal(t, "", checkAccount.Shard.Original)
+}
+
+func TestParseCodeMetadataForms(t *testing.T) {
+	p := Parser{}
+	for input, expected := range map[string][]byte{
+		`"upgradeable|payable"`: {0x01, 0x02},
+		`"0x0100"`:              {0x01, 0x00},
+		`"0x01|0x02"`:           {0x01, 0x02},
+		`"''ab"`:                []byte("ab"),
+	} {
+		obj, err := oj.ParseOrderedJSON([]byte(input))
+		require.Nil(t, err)
+		metadata, err := p.processCodeMetadata(obj)
+		require.Nil(t, err, input)
+		require.Equal(t, expected, metadata.Value, input)
+	}
+}
+
+func TestParseRelaxedJSON(t *testing.T) {
+	snippet := `
+	{
+		// the comment field is no longer needed
+		"step": "setState",
+		"accounts": {
+			"''acc1____________________________": {
+				"nonce": "1", /* the nonce */
+				"balance": "2",
+			},
+		},
+	}`
+	p := Parser{}
+	_, err := p.ParseScenarioStep(snippet)
+	require.NotNil(t, err)
+
+	p.RelaxedJSON = true
+	step, err := p.ParseScenarioStep(snippet)
+	require.Nil(t, err)
+	setStateStep, isSetState := step.(*mj.SetStateStep)
+	require.True(t, isSetState)
+	require.Len(t, setStateStep.Accounts, 1)
+	require.Equal(t, uint64(1), setStateStep.Accounts[0].Nonce.Value)
+}
+
+func TestParseDuplicateAccounts(t *testing.T) {
+	snippet := `
+	{
+		"step": "setState",
+		"accounts": {
+			"''acc1____________________________": {
+				"nonce": "1",
+				"storage": {
+					"''key": "1",
+					"0x6b6579": "2"
+				}
+			},
+			"0x616363315f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f5f": {
+				"nonce": "2"
+			}
+		}
+	}`
+	p := Parser{}
+	_, err := p.ParseScenarioStep(snippet)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "duplicate")
+
+	p.RelaxedJSON = true
+	_, err = p.ParseScenarioStep(snippet)
+	require.NotNil(t, err)
+
+	p.RelaxedJSON = false
+	p.AllowDuplicates = true
+	p.StartFile("duplicates.scen.json")
+	_, err = p.ParseScenarioStep(snippet)
+	require.Nil(t, err)
+	require.Len(t, p.Warnings, 2)
+	require.Equal(t, "duplicates.scen.json", p.Warnings[0].File)
+	require.Contains(t, p.Warnings[0].Error(), "duplicates.scen.json: duplicate storage key")
+	require.Contains(t, p.Warnings[1].Error(), "duplicate account address")
+
+	p.StartFile("next.scen.json")
+	require.Empty(t, p.Warnings)
+}
@@ -0,0 +1,114 @@
+package mandosjsonparse
+
+import (
+	"fmt"
+	"strings"
+
+	mhc "github.com/kalyan3104/dme-vm-util/mock-hook-crypto"
+)
+
+const signPrefix = "sign-"
+const pubkeyPrefix = "pubkey-"
+
+// signatureScheme produces deterministic keys and signatures, the keys are derived from names.
+type signatureScheme struct {
+	publicKey func(keyName string) []byte
+	sign      func(keyName string, msg []byte) ([]byte, error)
+}
+
+var signatureSchemes = map[string]signatureScheme{
+	"ed25519": {
+		publicKey: func(keyName string) []byte {
+			_, publicKey := mhc.Ed25519KeyFromSeed(keyName)
+			return publicKey
+		},
+		sign: func(keyName string, msg []byte) ([]byte, error) {
+			privateKey, _ := mhc.Ed25519KeyFromSeed(keyName)
+			return mhc.SignEd25519(privateKey, msg)
+		},
+	},
+	"secp256k1": {
+		publicKey: func(keyName string) []byte {
+			_, publicKey := mhc.Secp256k1KeyFromSeed(keyName)
+			return publicKey
+		},
+		sign: func(keyName string, msg []byte) ([]byte, error) {
+			privateKey, _ := mhc.Secp256k1KeyFromSeed(keyName)
+			return mhc.SignSecp256k1(privateKey, msg), nil
+		},
+	},
+	"bls": {
+		publicKey: func(keyName string) []byte {
+			_, publicKey := mhc.BLSKeyFromSeed(keyName)
+			return publicKey
+		},
+		sign: func(keyName string, msg []byte) ([]byte, error) {
+			privateKey, _ := mhc.BLSKeyFromSeed(keyName)
+			return mhc.SignBLS(privateKey, msg)
+		},
+	},
+}
+
+// parsePubkeyExpression interprets "pubkey-<scheme>:<key-name>", the argument is the part after "pubkey-".
+func parsePubkeyExpression(expr string) ([]byte, error) {
+	schemeName, keyName, found := cutString(expr, ":")
+	if !found {
+		return []byte{}, fmt.Errorf("invalid public key expression, expected pubkey-<scheme>:<key-name>: %s", pubkeyPrefix+expr)
+	}
+	scheme, isScheme := signatureSchemes[schemeName]
+	if !isScheme {
+		return []byte{}, fmt.Errorf("unknown signature scheme: %s", schemeName)
+	}
+	if len(keyName) == 0 {
+		return []byte{}, fmt.Errorf("missing key name: %s", pubkeyPrefix+expr)
+	}
+	return scheme.publicKey(keyName), nil
+}
+
+// parseSignExpression interprets "sign-<scheme>[-<hash>]:<key-name>:<message-expr>", the argument is the part after "sign-".
+// The message is itself a value expression. If a hash is specified (e.g. "sign-ed25519-keccak256:"), the hash of the message gets signed.
+func (p *Parser) parseSignExpression(expr string) ([]byte, error) {
+	schemeAndHash, rest, found := cutString(expr, ":")
+	if !found {
+		return []byte{}, fmt.Errorf("invalid signature expression, expected sign-<scheme>:<key-name>:<message>: %s", signPrefix+expr)
+	}
+	keyName, msgExpr, found := cutString(rest, ":")
+	if !found || len(keyName) == 0 {
+		return []byte{}, fmt.Errorf("invalid signature expression, expected sign-<scheme>:<key-name>:<message>: %s", signPrefix+expr)
+	}
+
+	schemeName, hashName, hasHash := cutString(schemeAndHash, "-")
+	scheme, isScheme := signatureSchemes[schemeName]
+	if !isScheme {
+		return []byte{}, fmt.Errorf("unknown signature scheme: %s", schemeName)
+	}
+
+	msg, err := p.parseAnyValueAsByteArray(msgExpr)
+	if err != nil {
+		return []byte{}, fmt.Errorf("cannot parse message to sign: %w", err)
+	}
+	if hasHash {
+		hashFunc, isHash := p.hashFunction(hashName)
+		if !isHash {
+			return []byte{}, fmt.Errorf("unknown message hash: %s", hashName)
+		}
+		msg, err = hashFunc(msg)
+		if err != nil {
+			return []byte{}, fmt.Errorf("error computing %s: %w", hashName, err)
+		}
+	}
+
+	signature, err := scheme.sign(keyName, msg)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error signing with %s: %w", schemeName, err)
+	}
+	return signature, nil
+}
+
+// cutString is strings.Cut, which is not available in go 1.17.
+func cutString(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -11,7 +11,7 @@ import (
 // ParseTestFile converts json string to object representation
 func (p *Parser) ParseTestFile(jsonString []byte) ([]*mj.Test, error) {
 
-	jobj, err := oj.ParseOrderedJSON(jsonString)
+	jobj, err := p.parseOrderedJSON(jsonString)
 	if err != nil {
 		return nil, err
 	}
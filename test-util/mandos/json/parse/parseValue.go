@@ -104,7 +104,7 @@ func (p *Parser) parseString(obj oj.OJsonObject) (string, error) {
 	if !isStr {
 		return "", errors.New("not a string value")
 	}
-	return str.Value, nil
+	return str.String(), nil
 }
 
 func IsStar(obj oj.OJsonObject) bool {
@@ -112,5 +112,5 @@ func IsStar(obj oj.OJsonObject) bool {
 	if !isStr {
 		return false
 	}
-	return str.Value == "*"
+	return str.String() == "*"
 }
@@ -1,6 +1,101 @@
 package mandosjsonparse
 
+import (
+	"bytes"
+	"fmt"
+
+	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
+)
+
 // Parser performs parsing of both json tests (older) and scenarios (new).
 type Parser struct {
 	FileResolver FileResolver
+
+	// HashProvider computes the hashes in value expressions.
+	// If not set, the crypto hook mock is used.
+	HashProvider HashProvider
+
+	// RelaxedJSON allows comments and trailing commas in the parsed files.
+	RelaxedJSON bool
+
+	// AllowDuplicates reports duplicate keys in Warnings instead of as errors.
+	// This covers both repeated JSON keys and keys that are written differently, but have the same value.
+	AllowDuplicates bool
+
+	// CurrentFile is the file being parsed, it gets recorded in the warnings.
+	CurrentFile string
+
+	// Warnings collects the tolerated duplicates of the current file.
+	Warnings []*ParseWarning
+}
+
+// ParseWarning is a problem that the parser tolerated, in a given file.
+type ParseWarning struct {
+	File string
+	Err  error
+}
+
+func (w *ParseWarning) Error() string {
+	if w.File == "" {
+		return w.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", w.File, w.Err)
+}
+
+// Unwrap yields the tolerated error.
+func (w *ParseWarning) Unwrap() error {
+	return w.Err
+}
+
+// StartFile sets the current file and clears the warnings of the previous one.
+func (p *Parser) StartFile(path string) {
+	p.CurrentFile = path
+	p.Warnings = nil
+}
+
+func (p *Parser) parseOrderedJSON(input []byte) (oj.OJsonObject, error) {
+	decoder := oj.NewDecoder(bytes.NewReader(input))
+	decoder.Relaxed = p.RelaxedJSON
+	decoder.AllowDuplicateKeys = p.AllowDuplicates
+	result, err := decoder.Decode()
+	for _, warning := range decoder.Warnings {
+		p.warn(warning)
+	}
+	return result, err
+}
+
+func (p *Parser) warn(err error) {
+	p.Warnings = append(p.Warnings, &ParseWarning{
+		File: p.CurrentFile,
+		Err:  err,
+	})
+}
+
+// duplicateChecker finds keys that are written differently, but evaluate to the same bytes,
+// e.g. an address given once in hex and once as a string.
+type duplicateChecker struct {
+	description string
+	originals   map[string]string
+}
+
+func newDuplicateChecker(description string) *duplicateChecker {
+	return &duplicateChecker{
+		description: description,
+		originals:   make(map[string]string),
+	}
+}
+
+// checkDuplicate fails on duplicates, unless they are allowed, then it only records a warning.
+func (p *Parser) checkDuplicate(checker *duplicateChecker, value []byte, original string) error {
+	firstOriginal, isDuplicate := checker.originals[string(value)]
+	if !isDuplicate {
+		checker.originals[string(value)] = original
+		return nil
+	}
+	err := fmt.Errorf("duplicate %s: \"%s\" and \"%s\" have the same value", checker.description, firstOriginal, original)
+	if !p.AllowDuplicates {
+		return err
+	}
+	p.warn(err)
+	return nil
 }
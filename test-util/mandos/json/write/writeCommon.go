@@ -26,6 +26,21 @@ func accountsToOJ(accounts []*mj.Account) oj.OJsonObject {
 		if len(account.AsyncCallData) > 0 {
 			acctOJ.Put("asyncCallData", stringToOJ(account.AsyncCallData))
 		}
+		if len(account.Owner.Original) > 0 {
+			acctOJ.Put("owner", byteArrayToOJ(account.Owner))
+		}
+		if len(account.Username.Original) > 0 {
+			acctOJ.Put("username", byteArrayToOJ(account.Username))
+		}
+		if len(account.CodeMetadata.Original) > 0 {
+			acctOJ.Put("codeMetadata", byteArrayToOJ(account.CodeMetadata))
+		}
+		if len(account.Shard.Original) > 0 {
+			acctOJ.Put("shard", uint64ToOJ(account.Shard))
+		}
+		if len(account.DeveloperRewards.Original) > 0 {
+			acctOJ.Put("developerRewards", bigIntToOJ(account.DeveloperRewards))
+		}
 
 		acctsOJ.Put(byteArrayToString(account.Address), acctOJ)
 	}
@@ -55,6 +70,21 @@ func checkAccountsToOJ(checkAccounts *mj.CheckAccounts) oj.OJsonObject {
 		if len(checkAccount.AsyncCallData) > 0 {
 			acctOJ.Put("asyncCallData", stringToOJ(checkAccount.AsyncCallData))
 		}
+		if isCheckBytesSpecified(checkAccount.Owner) {
+			acctOJ.Put("owner", checkBytesToOJ(checkAccount.Owner))
+		}
+		if isCheckBytesSpecified(checkAccount.Username) {
+			acctOJ.Put("username", checkBytesToOJ(checkAccount.Username))
+		}
+		if isCheckBytesSpecified(checkAccount.CodeMetadata) {
+			acctOJ.Put("codeMetadata", checkBytesToOJ(checkAccount.CodeMetadata))
+		}
+		if len(checkAccount.Shard.Original) > 0 {
+			acctOJ.Put("shard", checkUint64ToOJ(checkAccount.Shard))
+		}
+		if len(checkAccount.DeveloperRewards.Original) > 0 {
+			acctOJ.Put("developerRewards", checkBigIntToOJ(checkAccount.DeveloperRewards))
+		}
 
 		acctsOJ.Put(byteArrayToString(checkAccount.Address), acctOJ)
 	}
@@ -66,6 +96,64 @@ func checkAccountsToOJ(checkAccounts *mj.CheckAccounts) oj.OJsonObject {
 	return acctsOJ
 }
 
+func checkAsyncCallsToOJ(checkAsyncCalls *mj.CheckAsyncCalls) oj.OJsonObject {
+	asyncCallsOJ := oj.NewMap()
+	if !checkAsyncCalls.IgnorePending {
+		asyncCallsOJ.Put("pending", checkAsyncCallListToOJ(checkAsyncCalls.Pending))
+	}
+	if !checkAsyncCalls.IgnoreCompleted {
+		asyncCallsOJ.Put("completed", checkAsyncCallListToOJ(checkAsyncCalls.Completed))
+	}
+	return asyncCallsOJ
+}
+
+func checkAsyncCallListToOJ(checkAsyncCalls []*mj.CheckAsyncCall) oj.OJsonObject {
+	var asyncCallList []oj.OJsonObject
+	for _, checkAsyncCall := range checkAsyncCalls {
+		asyncCallOJ := oj.NewMap()
+		// fields that were missing from the original json are not checked and not written either
+		if len(checkAsyncCall.From.Original) > 0 {
+			asyncCallOJ.Put("from", checkBytesToOJ(checkAsyncCall.From))
+		}
+		if len(checkAsyncCall.To.Original) > 0 {
+			asyncCallOJ.Put("to", checkBytesToOJ(checkAsyncCall.To))
+		}
+		if len(checkAsyncCall.Function) > 0 {
+			asyncCallOJ.Put("function", stringToOJ(checkAsyncCall.Function))
+		}
+		if !checkAsyncCall.IgnoreArguments {
+			asyncCallOJ.Put("arguments", checkBytesListToOJ(checkAsyncCall.Arguments))
+		}
+		if len(checkAsyncCall.Value.Original) > 0 {
+			asyncCallOJ.Put("value", checkBigIntToOJ(checkAsyncCall.Value))
+		}
+		if len(checkAsyncCall.GasLimit.Original) > 0 {
+			asyncCallOJ.Put("gasLimit", checkUint64ToOJ(checkAsyncCall.GasLimit))
+		}
+		if len(checkAsyncCall.Callback) > 0 {
+			asyncCallOJ.Put("callback", stringToOJ(checkAsyncCall.Callback))
+		}
+		if len(checkAsyncCall.Status.Original) > 0 {
+			asyncCallOJ.Put("status", checkBigIntToOJ(checkAsyncCall.Status))
+		}
+		if !checkAsyncCall.IgnoreOut {
+			asyncCallOJ.Put("out", checkBytesListToOJ(checkAsyncCall.Out))
+		}
+		asyncCallList = append(asyncCallList, asyncCallOJ)
+	}
+	asyncCallOJList := oj.OJsonList(asyncCallList)
+	return &asyncCallOJList
+}
+
+func checkBytesListToOJ(checkBytesList []mj.JSONCheckBytes) oj.OJsonObject {
+	var checkBytesOJList []oj.OJsonObject
+	for _, checkBytes := range checkBytesList {
+		checkBytesOJList = append(checkBytesOJList, checkBytesToOJ(checkBytes))
+	}
+	listOJ := oj.OJsonList(checkBytesOJList)
+	return &listOJ
+}
+
 func blockHashesToOJ(blockHashes []mj.JSONBytes) oj.OJsonObject {
 	var blockhashesList []oj.OJsonObject
 	for _, blh := range blockHashes {
@@ -75,6 +163,14 @@ func blockHashesToOJ(blockHashes []mj.JSONBytes) oj.OJsonObject {
 	return &blockhashesOJ
 }
 
+func blockHashesByNonceToOJ(blockHashes []*mj.BlockHash) oj.OJsonObject {
+	blockHashesOJ := oj.NewMap()
+	for _, blockHash := range blockHashes {
+		blockHashesOJ.Put(blockHash.Nonce.Original, byteArrayToOJ(blockHash.Hash))
+	}
+	return blockHashesOJ
+}
+
 func resultToOJ(res *mj.TransactionResult) oj.OJsonObject {
 	resultOJ := oj.NewMap()
 
@@ -186,6 +282,12 @@ func checkBytesToString(checkBytes mj.JSONCheckBytes) string {
 	return checkBytes.Original
 }
 
+// isCheckBytesSpecified is false for optional fields missing from the check, which accept any value.
+// Explicit empty values, e.g. `"owner": ""`, count as specified.
+func isCheckBytesSpecified(checkBytes mj.JSONCheckBytes) bool {
+	return !checkBytes.IsStar || len(checkBytes.Original) > 0
+}
+
 func checkBytesToOJ(checkBytes mj.JSONCheckBytes) oj.OJsonObject {
 	return &oj.OJsonString{Value: checkBytesToString(checkBytes)}
 }
@@ -52,14 +52,31 @@ func ScenarioToOrderedJSON(scenario *mj.Scenario) oj.OJsonObject {
 			if step.CurrentBlockInfo != nil {
 				stepOJ.Put("currentBlockInfo", blockInfoToOJ(step.CurrentBlockInfo))
 			}
-			if len(step.BlockHashes) > 0 {
+			if len(step.BlockHashesByNonce) > 0 {
+				stepOJ.Put("blockHashes", blockHashesByNonceToOJ(step.BlockHashesByNonce))
+			} else if len(step.BlockHashes) > 0 {
 				stepOJ.Put("blockHashes", blockHashesToOJ(step.BlockHashes))
 			}
 		case *mj.CheckStateStep:
 			if len(step.Comment) > 0 {
 				stepOJ.Put("comment", stringToOJ(step.Comment))
 			}
-			stepOJ.Put("accounts", checkAccountsToOJ(step.CheckAccounts))
+			if step.CheckAccounts != nil {
+				stepOJ.Put("accounts", checkAccountsToOJ(step.CheckAccounts))
+			}
+			if step.CheckAsyncCalls != nil {
+				stepOJ.Put("asyncCalls", checkAsyncCallsToOJ(step.CheckAsyncCalls))
+			}
+		case *mj.NextBlockStep:
+			if len(step.Comment) > 0 {
+				stepOJ.Put("comment", stringToOJ(step.Comment))
+			}
+			if len(step.Rounds.Original) > 0 {
+				stepOJ.Put("rounds", uint64ToOJ(step.Rounds))
+			}
+			if len(step.Seconds.Original) > 0 {
+				stepOJ.Put("seconds", uint64ToOJ(step.Seconds))
+			}
 		case *mj.TxStep:
 			if len(step.TxIdent) > 0 {
 				stepOJ.Put("txId", stringToOJ(step.TxIdent))
@@ -142,6 +159,9 @@ func blockInfoToOJ(blockInfo *mj.BlockInfo) oj.OJsonObject {
 	if len(blockInfo.BlockEpoch.Original) > 0 {
 		blockInfoOJ.Put("blockEpoch", uint64ToOJ(blockInfo.BlockEpoch))
 	}
+	if len(blockInfo.BlockRandomSeed.Value) > 0 {
+		blockInfoOJ.Put("blockRandomSeed", byteArrayToOJ(blockInfo.BlockRandomSeed))
+	}
 
 	return blockInfoOJ
 }
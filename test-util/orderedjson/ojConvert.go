@@ -0,0 +1,137 @@
+package orderedjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ToInterface converts an ordered JSON tree to the generic values used by encoding/json:
+// map[string]interface{}, []interface{}, string and bool.
+// Key order is lost. Escape sequences in strings are interpreted.
+func ToInterface(j OJsonObject) (interface{}, error) {
+	switch value := j.(type) {
+	case *OJsonMap:
+		result := make(map[string]interface{}, value.Size())
+		for _, kvp := range value.OrderedKV {
+			key, err := unescapeString(kvp.Key)
+			if err != nil {
+				return nil, err
+			}
+			result[key], err = ToInterface(kvp.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case *OJsonList:
+		collection := value.AsList()
+		result := make([]interface{}, len(collection))
+		for i, elem := range collection {
+			var err error
+			result[i], err = ToInterface(elem)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case *OJsonString:
+		return unescapeString(value.String())
+	case *OJsonBool:
+		return bool(*value), nil
+	default:
+		return nil, fmt.Errorf("unknown ordered JSON type %T", j)
+	}
+}
+
+// FromInterface builds an ordered JSON tree from any value that encoding/json can marshal,
+// e.g. generic maps and lists, structs or json.RawMessage.
+// Key order follows the encoding/json output: raw messages and structs keep theirs, Go maps get sorted keys.
+// Since the ordered tree has no numbers, numbers become strings. Null values are not supported.
+func FromInterface(value interface{}) (OJsonObject, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(&buffer)
+	decoder.UseNumber()
+	return fromJSONTokens(decoder)
+}
+
+// fromJSONTokens reads the next value from the encoding/json token stream.
+func fromJSONTokens(decoder *json.Decoder) (OJsonObject, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			result := NewMap()
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				elem, err := fromJSONTokens(decoder)
+				if err != nil {
+					return nil, err
+				}
+				result.Put(escapeString(keyToken.(string)), elem)
+			}
+			_, err = decoder.Token() // '}'
+			return result, err
+		}
+		result := OJsonList{}
+		for decoder.More() {
+			elem, err := fromJSONTokens(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, elem)
+		}
+		_, err = decoder.Token() // ']'
+		return &result, err
+	case string:
+		return &OJsonString{Value: escapeString(value)}, nil
+	case json.Number:
+		return &OJsonString{Value: value.String()}, nil
+	case bool:
+		result := OJsonBool(value)
+		return &result, nil
+	case nil:
+		return nil, errors.New("null values are not supported")
+	default:
+		return nil, fmt.Errorf("unexpected JSON token %v", token)
+	}
+}
+
+// escapeString yields the string as it would appear between quotes in JSON, since that is how the tree stores strings.
+func escapeString(value string) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	encoded := strings.TrimSuffix(buffer.String(), "\n")
+	return encoded[1 : len(encoded)-1]
+}
+
+// unescapeString interprets the escape sequences in a string from the tree.
+func unescapeString(raw string) (string, error) {
+	if !strings.Contains(raw, "\\") {
+		return raw, nil
+	}
+	var result string
+	err := json.Unmarshal([]byte("\""+raw+"\""), &result)
+	if err != nil {
+		return "", fmt.Errorf("invalid string \"%s\": %w", raw, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package orderedjson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToInterface(t *testing.T) {
+	result, err := ParseOrderedJSON([]byte(`{"b": "quote \" here", "a": ["x", true, {}]}`))
+	require.Nil(t, err)
+
+	value, err := ToInterface(result)
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{
+		"b": `quote " here`,
+		"a": []interface{}{"x", true, map[string]interface{}{}},
+	}, value)
+}
+
+func TestFromInterface(t *testing.T) {
+	// raw messages keep their key order
+	result, err := FromInterface(json.RawMessage(`{"b": "quote \" <here>", "a": [1, false]}`))
+	require.Nil(t, err)
+	require.Equal(t, `{"b":"quote \" <here>","a":["1",false]}`, JSONStringWithOptions(result, FormatOptions{Minify: true}))
+
+	// so do structs
+	result, err = FromInterface(struct {
+		Step string `json:"step"`
+		Gas  int    `json:"gas"`
+	}{Step: "scCall", Gas: 5})
+	require.Nil(t, err)
+	require.Equal(t, `{"step":"scCall","gas":"5"}`, JSONStringWithOptions(result, FormatOptions{Minify: true}))
+
+	// maps get sorted keys
+	result, err = FromInterface(map[string]interface{}{"b": "1", "a": []interface{}{"2"}})
+	require.Nil(t, err)
+	require.Equal(t, `{"a":["2"],"b":"1"}`, JSONStringWithOptions(result, FormatOptions{Minify: true}))
+
+	_, err = FromInterface(map[string]interface{}{"a": nil})
+	require.NotNil(t, err)
+}
+
+func TestPointer(t *testing.T) {
+	root, err := ParseOrderedJSON([]byte(`{"steps": [{"tx": {"from": "alice", "to": "bob"}}], "a/b": {"~": "x"}}`))
+	require.Nil(t, err)
+
+	value, err := Get(root, "/steps/0/tx/from")
+	require.Nil(t, err)
+	require.Equal(t, "alice", value.(*OJsonString).Value)
+
+	value, err = Get(root, "/a~1b/~0")
+	require.Nil(t, err)
+	require.Equal(t, "x", value.(*OJsonString).Value)
+
+	value, err = Get(root, "")
+	require.Nil(t, err)
+	require.Equal(t, root, value)
+
+	_, err = Get(root, "/steps/1/tx")
+	require.EqualError(t, err, "/steps/1: list index 1 out of range")
+	_, err = Get(root, "/steps/00")
+	require.NotNil(t, err)
+	_, err = Get(root, "/steps/0/tx/from/x")
+	require.NotNil(t, err)
+	_, err = Get(root, "steps")
+	require.NotNil(t, err)
+
+	// replacing keeps the key order, new keys go at the end
+	require.Nil(t, Set(root, "/steps/0/tx/from", &OJsonString{Value: "carol"}))
+	require.Nil(t, Set(root, "/steps/0/tx/value", &OJsonString{Value: "5"}))
+	require.Nil(t, Set(root, "/steps/-", NewMap()))
+	require.Nil(t, Set(root, "/steps/2", NewMap()))
+	require.NotNil(t, Set(root, "/steps/4", NewMap()))
+	require.NotNil(t, Set(root, "", NewMap()))
+	require.Equal(t, `{"steps":[{"tx":{"from":"carol","to":"bob","value":"5"}},{},{}],"a/b":{"~":"x"}}`,
+		JSONStringWithOptions(root, FormatOptions{Minify: true}))
+
+	require.Nil(t, Delete(root, "/steps/1"))
+	require.Nil(t, Delete(root, "/steps/0/tx/to"))
+	require.NotNil(t, Delete(root, "/steps/0/tx/to"))
+	require.Equal(t, `{"steps":[{"tx":{"from":"carol","value":"5"}},{}],"a/b":{"~":"x"}}`,
+		JSONStringWithOptions(root, FormatOptions{Minify: true}))
+
+	// deleted keys can be added again
+	require.Nil(t, Set(root, "/steps/0/tx/to", &OJsonString{Value: "dan"}))
+	value, err = Get(root, "/steps/0/tx/to")
+	require.Nil(t, err)
+	require.Equal(t, "dan", value.(*OJsonString).Value)
+}
@@ -1,12 +1,8 @@
 package orderedjson
 
-import (
-	"strings"
-)
-
 // OJsonObject is an ordered JSON tree object interface.
 type OJsonObject interface {
-	writeJSON(sb *strings.Builder, indent int)
+	writeJSON(w *jsonWriter, indent int)
 }
 
 // OJsonKeyValuePair is a key-value pair in a JSON map.
@@ -14,25 +10,58 @@ type OJsonObject interface {
 type OJsonKeyValuePair struct {
 	Key   string
 	Value OJsonObject
+
+	// Comments holds the comments before the key, only in relaxed mode.
+	Comments []string
 }
 
 // OJsonMap is an ordered map, actually a list of key value pairs.
 type OJsonMap struct {
 	KeySet    map[string]bool
 	OrderedKV []*OJsonKeyValuePair
+
+	// Comments holds the comments before the map, only in relaxed mode.
+	Comments []string
+
+	// EndComments holds the comments after the last key value pair, only in relaxed mode.
+	EndComments []string
 }
 
 // OJsonList is a JSON list.
+// Its comments are kept in a CommentTable, since it has no fields to hold them.
 type OJsonList []OJsonObject
 
 // OJsonString is a JSON string value.
+// The value is kept as it appears between the quotes in JSON: escape sequences are not interpreted.
 type OJsonString struct {
+	// Value is the string value. Lazy strings leave it empty until String is first called.
 	Value string
+
+	// Comments holds the comments before the string, only in relaxed mode.
+	Comments []string
+
+	// lazyValue holds the raw contents of large strings until they are first needed, see Decoder.LazyStringThreshold.
+	// It points into the decoder input, it is not a copy. It only counts while Value is empty.
+	lazyValue []byte
 }
 
 // OJsonBool is a JSON bool value.
+// Its comments are kept in a CommentTable, since it has no fields to hold them.
 type OJsonBool bool
 
+// NodeComments holds the comments of a list or a bool.
+type NodeComments struct {
+	// Comments are the comments before the node.
+	Comments []string
+
+	// EndComments are the comments after the last element of a list.
+	EndComments []string
+}
+
+// CommentTable holds the comments of the lists and bools in a tree, by node.
+// Maps, key value pairs and strings hold their own comments.
+type CommentTable map[OJsonObject]*NodeComments
+
 // NewMap is a create new ordered "map" instance.
 func NewMap() *OJsonMap {
 	KeySet := make(map[string]bool)
@@ -40,13 +69,16 @@ func NewMap() *OJsonMap {
 }
 
 // Put puts into map. Does nothing if key exists in map.
-func (j *OJsonMap) Put(key string, value OJsonObject) {
+// Yields false if the key was already in the map.
+func (j *OJsonMap) Put(key string, value OJsonObject) bool {
 	_, alreadyInserted := j.KeySet[key]
-	if !alreadyInserted {
-		j.KeySet[key] = true
-		keyValuePair := &OJsonKeyValuePair{Key: key, Value: value}
-		j.OrderedKV = append(j.OrderedKV, keyValuePair)
+	if alreadyInserted {
+		return false
 	}
+	j.KeySet[key] = true
+	keyValuePair := &OJsonKeyValuePair{Key: key, Value: value}
+	j.OrderedKV = append(j.OrderedKV, keyValuePair)
+	return true
 }
 
 // Size yields the size of ordered map.
@@ -62,7 +94,36 @@ func (j *OJsonMap) RefreshKeySet() {
 	}
 }
 
+// String yields the string value. Lazy strings get converted into Value on the first call.
+func (j *OJsonString) String() string {
+	if j.IsLazy() {
+		j.Value = string(j.lazyValue)
+	}
+	j.lazyValue = nil
+	return j.Value
+}
+
+// SetValue replaces the string value, also for lazy strings that were not converted yet.
+// The value should be escaped as in JSON.
+func (j *OJsonString) SetValue(value string) {
+	j.Value = value
+	j.lazyValue = nil
+}
+
+// IsLazy is true for strings that were not converted yet, see Decoder.LazyStringThreshold.
+func (j *OJsonString) IsLazy() bool {
+	return j.lazyValue != nil && j.Value == ""
+}
+
 // AsList converts a JSON list to a slice of objects.
 func (j *OJsonList) AsList() []OJsonObject {
 	return []OJsonObject(*j)
 }
+
+// get yields the comments of a node, nil if it has none. The table can be nil.
+func (table CommentTable) get(j OJsonObject) *NodeComments {
+	if table == nil {
+		return nil
+	}
+	return table[j]
+}
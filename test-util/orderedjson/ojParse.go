@@ -2,242 +2,344 @@ package orderedjson
 
 import (
 	"bytes"
-	"errors"
-	"strings"
+	"fmt"
+	"io"
 )
 
-type jsonParserState interface {
-}
+// Decoder reads an ordered JSON tree from an input stream.
+//
+// Like the rest of the package, it only handles maps, lists, strings and bools.
+// Strings are kept as they appear in the input: escape sequences are not interpreted.
+type Decoder struct {
+	tokenizer       *Tokenizer
+	input           []byte
+	pendingComments []string
 
-type jsonParserStateAnyObjPlaceholder struct {
-}
+	// LazyStringThreshold enables lazy strings: strings at least this long are not copied,
+	// they point into the input and only get converted to Go strings when OJsonString.String is first called.
+	// This saves time and memory for large values that are never looked at, e.g. embedded contract code.
+	// It only works for decoders created with NewBytesDecoder, the input must not be modified while lazy strings remain.
+	// Zero (the default) disables lazy strings.
+	LazyStringThreshold int
+
+	// Relaxed allows "//" and "/* */" comments and trailing commas in maps and lists.
+	// Comments are kept in the tree: before map keys in OJsonKeyValuePair.Comments,
+	// before maps and strings in their Comments field and before the end of maps in OJsonMap.EndComments.
+	// The comments of lists and bools go to the Comments table instead.
+	// Comments before a comma are attached to the next element, or to the end of the map or list.
+	// Comments after a root string or bool are added to its Comments, since there is nothing after it to hold them.
+	Relaxed bool
+
+	// Comments holds the comments of lists and bools, in relaxed mode.
+	// Pass it in FormatOptions.Comments to write them back.
+	Comments CommentTable
+
+	// AllowDuplicateKeys reports duplicate map keys in Warnings instead of failing. Only the first value is kept.
+	AllowDuplicateKeys bool
 
-type jsonParserStateSingleValue struct {
-	buffer       bytes.Buffer
-	stringEscape bool
+	// Warnings collects the tolerated duplicate keys.
+	Warnings []error
 }
 
-type jsonParserStateMap struct {
-	currentMap *OJsonMap
+// DuplicateKeyError signals a key that appears twice in the same map. Only the first value is kept.
+type DuplicateKeyError struct {
+	Key         string
+	Line        int
+	Column      int
+	FirstLine   int
+	FirstColumn int
 }
 
-type jsonStateMapKeyValue struct {
-	keyBuffer bytes.Buffer
-	state     int // 0=key, 1=':', 2=value
-	currentKV OJsonKeyValuePair
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate map key \"%s\" at line %d, column %d, first defined at line %d, column %d",
+		e.Key, e.Line, e.Column, e.FirstLine, e.FirstColumn)
 }
 
-type jsonParserStateList struct {
-	list OJsonList
+// NewDecoder creates a decoder that reads from the given input.
+func NewDecoder(reader io.Reader) *Decoder {
+	return &Decoder{
+		tokenizer: NewTokenizer(reader),
+	}
 }
 
-func isWhitespace(c byte) bool {
-	return c == ' ' || c == '\n' || c == '\r' || c == '\t'
+// NewBytesDecoder creates a decoder for an input that is entirely in memory.
+// Unlike NewDecoder, it supports lazy strings.
+func NewBytesDecoder(input []byte) *Decoder {
+	decoder := NewDecoder(bytes.NewReader(input))
+	decoder.input = input
+	return decoder
 }
 
 // ParseOrderedJSON parses JSON preserving order in maps
 func ParseOrderedJSON(input []byte) (OJsonObject, error) {
-	stateStack := &jsonParserStateStack{}
-	stateStack.push(&jsonParserStateAnyObjPlaceholder{})
-	var pendingResult OJsonObject
-
-	for i, c := range input {
-		done := false
-		for !done {
-			done = true
-
-			if stateStack.size() == 0 {
-				if isWhitespace(c) {
-					continue
-				} else {
-					return nil, errors.New("unexpected characters at the end")
-				}
-			}
+	return NewBytesDecoder(input).Decode()
+}
 
-			state := stateStack.peek()
-			switch specificState := state.(type) {
-			case *jsonParserStateAnyObjPlaceholder:
-				if pendingResult != nil {
-					return nil, errors.New("invalid state")
-				}
-				if isWhitespace(c) {
-					// leading whitespace, ignore
-				} else if c == '{' {
-					// replace with map state
-					stateStack.replaceTop(&jsonParserStateMap{currentMap: NewMap()})
-				} else if c == '[' {
-					// replace with list state
-					stateStack.replaceTop(&jsonParserStateList{})
-				} else if c == ']' || c == '}' || c == ',' {
-					return nil, errors.New("misplaced character")
-				} else {
-					// replace with single value
-					stateStack.replaceTop(&jsonParserStateSingleValue{})
-					done = false
-				}
-			case *jsonParserStateSingleValue:
-				if specificState.buffer.Len() == 0 {
-					specificState.stringEscape = (c == '"')
-					specificState.buffer.WriteByte(c)
-				} else {
-					prevChar := input[i-1]
-					if specificState.stringEscape {
-						specificState.buffer.WriteByte(c)
-						if c == '"' && prevChar != '\\' {
-							stateStack.pop()
-							var err error
-							pendingResult, err = specificState.finalize()
-							if err != nil {
-								return nil, err
-							}
-						}
-					} else {
-						if c == ']' || c == '}' || c == ',' || isWhitespace(c) {
-							stateStack.pop()
-							var err error
-							pendingResult, err = specificState.finalize()
-							if err != nil {
-								return nil, err
-							}
-							done = false
-						} else {
-							specificState.buffer.WriteByte(c)
-						}
-					}
-				}
-			case *jsonParserStateList:
-				if pendingResult != nil {
-					specificState.list = append(specificState.list, pendingResult)
-					pendingResult = nil
-				}
-				if isWhitespace(c) {
-					// ignore
-				} else {
-					if c == ']' {
-						pendingResult = &specificState.list
-						stateStack.pop()
-					} else if len(specificState.list) == 0 {
-						// new empty list
-						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
-						done = false
-					} else if c == ',' {
-						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
-					}
-				}
-			case *jsonParserStateMap:
-				if isWhitespace(c) {
-					// ignore
-				} else if c == '}' {
-					pendingResult = specificState.currentMap
-					stateStack.pop()
-				} else if c == ',' {
-					stateStack.push(&jsonStateMapKeyValue{})
-				} else if specificState.currentMap.Size() == 0 {
-					stateStack.push(&jsonStateMapKeyValue{})
-					done = false
-				} else {
-					return nil, errors.New("invalid map state")
-				}
-			case *jsonStateMapKeyValue:
-				switch specificState.state {
-				case 0: // key
-					if specificState.keyBuffer.Len() == 0 {
-						if isWhitespace(c) {
-							// ignore
-						} else {
-							if c != '"' {
-								return nil, errors.New("map key must start with a quote")
-							}
-							specificState.keyBuffer.WriteByte(c)
-						}
-					} else {
-						specificState.keyBuffer.WriteByte(c)
-						prevChar := input[i-1]
-						if c == '"' && prevChar != '\\' {
-							specificState.state = 1
-						}
-					}
-				case 1: // ':'
-					if isWhitespace(c) {
-						// ignore
-					} else if c == ':' {
-						specificState.state = 2
-						stateStack.push(&jsonParserStateAnyObjPlaceholder{})
-					} else {
-						return nil, errors.New("invalid character in map definition, colon expected")
-					}
-				case 2: // value
-					if pendingResult == nil {
-						return nil, errors.New("missing value in map")
-					}
-					key := specificState.keyBuffer.String()
-					if !strings.HasPrefix(key, "\"") || !strings.HasSuffix(key, "\"") {
-						return nil, errors.New("map key should be a string enclosed in quotes")
-					}
-					key = key[1 : len(key)-1]
-					stateStack.pop()
-					mapState, isMap := stateStack.peek().(*jsonParserStateMap)
-					if !isMap {
-						return nil, errors.New("map key value state, but no map state underneath")
-					}
-					mapState.currentMap.Put(key, pendingResult)
-					pendingResult = nil
-					done = false
-				default:
-					return nil, errors.New("unknown jsonStateMapKeyValue state")
-				}
-			default:
-				return nil, errors.New("invalid parser state")
-			}
+// ParseOrderedJSONRelaxed parses JSON preserving order in maps, also allowing comments and trailing commas.
+func ParseOrderedJSONRelaxed(input []byte) (OJsonObject, error) {
+	decoder := NewBytesDecoder(input)
+	decoder.Relaxed = true
+	return decoder.Decode()
+}
+
+// Decode reads the entire input, which must consist of exactly one JSON value.
+func (d *Decoder) Decode() (OJsonObject, error) {
+	token, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	result, err := d.decodeValue(token)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err = d.next()
+	if err != nil {
+		return nil, err
+	}
+	if token.Kind != TokenEOF {
+		return nil, syntaxError(token, "unexpected characters at the end")
+	}
+	// comments after the last value
+	if len(d.pendingComments) > 0 {
+		switch value := result.(type) {
+		case *OJsonMap:
+			value.EndComments = append(value.EndComments, d.takeComments()...)
+		case *OJsonList:
+			comments := d.nodeComments(value)
+			comments.EndComments = append(comments.EndComments, d.takeComments()...)
+		case *OJsonString:
+			value.Comments = append(value.Comments, d.takeComments()...)
+		case *OJsonBool:
+			comments := d.nodeComments(value)
+			comments.Comments = append(comments.Comments, d.takeComments()...)
 		}
 	}
+	return result, nil
+}
 
-	if stateStack.size() != 0 {
-		return nil, errors.New("state stack should be empty at the end")
+func (d *Decoder) next() (Token, error) {
+	d.tokenizer.AllowComments = d.Relaxed
+	token, err := d.tokenizer.Next()
+	if err != nil {
+		return Token{}, err
 	}
+	d.pendingComments = append(d.pendingComments, token.Comments...)
+	return token, nil
+}
 
-	return pendingResult, nil
+// nodeComments yields the entry of a list or bool in the comment table, creating it if needed.
+func (d *Decoder) nodeComments(j OJsonObject) *NodeComments {
+	if d.Comments == nil {
+		d.Comments = make(CommentTable)
+	}
+	comments, found := d.Comments[j]
+	if !found {
+		comments = &NodeComments{}
+		d.Comments[j] = comments
+	}
+	return comments
 }
 
-func (s *jsonParserStateSingleValue) finalize() (OJsonObject, error) {
-	str := s.buffer.String()
-	if strings.HasPrefix(str, "\"") && strings.HasSuffix(str, "\"") {
-		str = str[1 : len(str)-1]
-		return &OJsonString{Value: str}, nil
+// setComments records the comments before a list or bool, if any.
+func (d *Decoder) setComments(j OJsonObject) {
+	comments := d.takeComments()
+	if len(comments) > 0 {
+		d.nodeComments(j).Comments = comments
 	}
-	if str == "true" {
-		result := OJsonBool(true)
-		return &result, nil
+}
+
+// setEndComments records the comments before the end of a list, if any.
+func (d *Decoder) setEndComments(j *OJsonList) {
+	comments := d.takeComments()
+	if len(comments) > 0 {
+		d.nodeComments(j).EndComments = comments
 	}
-	if str == "false" {
-		result := OJsonBool(false)
-		return &result, nil
+}
+
+// takeComments yields the comments read so far that were not attached to any node.
+func (d *Decoder) takeComments() []string {
+	comments := d.pendingComments
+	d.pendingComments = nil
+	return comments
+}
+
+func (d *Decoder) decodeValue(token Token) (OJsonObject, error) {
+	switch token.Kind {
+	case TokenBeginMap:
+		return d.decodeMap()
+	case TokenBeginList:
+		return d.decodeList()
+	case TokenString:
+		result := d.newString(token)
+		result.Comments = d.takeComments()
+		return result, nil
+	case TokenLiteral:
+		result, err := decodeLiteral(token)
+		if err != nil {
+			return nil, err
+		}
+		d.setComments(result)
+		return result, nil
+	case TokenEOF:
+		return nil, syntaxError(token, "unexpected end of input, value expected")
+	default:
+		return nil, syntaxError(token, "misplaced %s, value expected", token.Kind)
 	}
-	return nil, errors.New("Invalid value: " + str)
 }
 
-type jsonParserStateStack struct {
-	stack []jsonParserState
+func (d *Decoder) decodeMap() (OJsonObject, error) {
+	result := NewMap()
+	result.Comments = d.takeComments()
+	keyTokens := make(map[string]Token)
+	token, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if token.Kind == TokenEndMap {
+			// an empty map, or a trailing comma in relaxed mode
+			if result.Size() > 0 && !d.Relaxed {
+				return nil, syntaxError(token, "trailing comma in map")
+			}
+			result.EndComments = d.takeComments()
+			return result, nil
+		}
+		if token.Kind != TokenString {
+			return nil, syntaxError(token, "map key should be a string enclosed in quotes")
+		}
+		key := string(token.Value)
+		keyToken := token
+		keyComments := d.takeComments()
+
+		token, err = d.next()
+		if err != nil {
+			return nil, err
+		}
+		if token.Kind != TokenColon {
+			return nil, syntaxError(token, "invalid character in map definition, colon expected")
+		}
+
+		token, err = d.next()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decodeValue(token)
+		if err != nil {
+			return nil, err
+		}
+		if result.Put(key, value) {
+			result.OrderedKV[len(result.OrderedKV)-1].Comments = keyComments
+			keyTokens[key] = keyToken
+		} else {
+			err = d.duplicateKey(key, keyTokens[key], keyToken)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		token, err = d.next()
+		if err != nil {
+			return nil, err
+		}
+		switch token.Kind {
+		case TokenEndMap:
+			result.EndComments = d.takeComments()
+			return result, nil
+		case TokenComma:
+			token, err = d.next()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, syntaxError(token, "misplaced %s in map, ',' or '}' expected", token.Kind)
+		}
+	}
 }
 
-func (s *jsonParserStateStack) push(state jsonParserState) {
-	s.stack = append(s.stack, state)
+func (d *Decoder) decodeList() (OJsonObject, error) {
+	result := &OJsonList{}
+	d.setComments(result)
+	token, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if token.Kind == TokenEndList {
+			// an empty list, or a trailing comma in relaxed mode
+			if len(*result) > 0 && !d.Relaxed {
+				return nil, syntaxError(token, "trailing comma in list")
+			}
+			d.setEndComments(result)
+			return result, nil
+		}
+		value, err := d.decodeValue(token)
+		if err != nil {
+			return nil, err
+		}
+		*result = append(*result, value)
+
+		token, err = d.next()
+		if err != nil {
+			return nil, err
+		}
+		switch token.Kind {
+		case TokenEndList:
+			d.setEndComments(result)
+			return result, nil
+		case TokenComma:
+			token, err = d.next()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, syntaxError(token, "misplaced %s in list, ',' or ']' expected", token.Kind)
+		}
+	}
 }
 
-func (s *jsonParserStateStack) replaceTop(state jsonParserState) {
-	s.stack[len(s.stack)-1] = state
+// duplicateKey fails, unless duplicate keys are allowed, then it only records a warning.
+func (d *Decoder) duplicateKey(key string, first Token, duplicate Token) error {
+	err := &DuplicateKeyError{
+		Key:         key,
+		Line:        duplicate.Line,
+		Column:      duplicate.Column,
+		FirstLine:   first.Line,
+		FirstColumn: first.Column,
+	}
+	if !d.AllowDuplicateKeys {
+		return err
+	}
+	d.Warnings = append(d.Warnings, err)
+	return nil
 }
 
-func (s *jsonParserStateStack) peek() jsonParserState {
-	return s.stack[len(s.stack)-1]
+// newString copies the token value, since the tokenizer reuses its buffer.
+// Lazy strings refer to the same bytes in the input instead, which do not change.
+func (d *Decoder) newString(token Token) *OJsonString {
+	if d.input != nil && d.LazyStringThreshold > 0 && len(token.Value) >= d.LazyStringThreshold {
+		start := token.Offset + 1 // after the opening quote
+		end := start + int64(len(token.Value))
+		return &OJsonString{lazyValue: d.input[start:end:end]}
+	}
+	return &OJsonString{Value: string(token.Value)}
 }
 
-func (s *jsonParserStateStack) pop() jsonParserState {
-	top := s.stack[len(s.stack)-1]
-	s.stack = s.stack[0 : len(s.stack)-1]
-	return top
+func decodeLiteral(token Token) (*OJsonBool, error) {
+	switch string(token.Value) {
+	case "true":
+		result := OJsonBool(true)
+		return &result, nil
+	case "false":
+		result := OJsonBool(false)
+		return &result, nil
+	default:
+		return nil, syntaxError(token, "invalid value: %s", token.Value)
+	}
 }
 
-func (s *jsonParserStateStack) size() int {
-	return len(s.stack)
+func syntaxError(token Token, format string, args ...interface{}) error {
+	return fmt.Errorf("invalid JSON at line %d, column %d: %s", token.Line, token.Column, fmt.Sprintf(format, args...))
 }
@@ -0,0 +1,346 @@
+package orderedjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOrderedJSON(t *testing.T) {
+	input := `{
+		"b": "1",
+		"a": ["x", true, false, [], {}],
+		"escaped": "quote \" and backslash \\",
+		"c": {"d": ""}
+	}`
+	result, err := ParseOrderedJSON([]byte(input))
+	require.Nil(t, err)
+
+	resultMap, isMap := result.(*OJsonMap)
+	require.True(t, isMap)
+	require.Equal(t, 4, resultMap.Size())
+	require.Equal(t, "b", resultMap.OrderedKV[0].Key)
+	require.Equal(t, "a", resultMap.OrderedKV[1].Key)
+	require.Equal(t, `quote \" and backslash \\`, resultMap.OrderedKV[2].Value.(*OJsonString).String())
+
+	list := resultMap.OrderedKV[1].Value.(*OJsonList).AsList()
+	require.Len(t, list, 5)
+	require.Equal(t, OJsonBool(true), *list[1].(*OJsonBool))
+
+	// writing and parsing again yields the same JSON
+	written := JSONString(result)
+	reparsed, err := ParseOrderedJSON([]byte(written))
+	require.Nil(t, err)
+	require.Equal(t, written, JSONString(reparsed))
+}
+
+func TestParseOrderedJSONErrors(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`{`,
+		`{"a" "b"}`,
+		`{a: "b"}`,
+		`{"a": "b",}`,
+		`["a",]`,
+		`["a" "b"]`,
+		`"unterminated`,
+		`"a" "b"`,
+		`123`,
+		`]`,
+	} {
+		_, err := ParseOrderedJSON([]byte(input))
+		require.NotNil(t, err, "input: %s", input)
+	}
+}
+
+func TestDecoderSmallReads(t *testing.T) {
+	// a string longer than the reader buffer, read one byte at a time
+	longValue := strings.Repeat("ab\\\"", 30000)
+	input := `{"long": "` + longValue + `"}`
+	result, err := NewDecoder(iotest.OneByteReader(strings.NewReader(input))).Decode()
+	require.Nil(t, err)
+	require.Equal(t, longValue, result.(*OJsonMap).OrderedKV[0].Value.(*OJsonString).String())
+}
+
+func TestDecoderLazyStrings(t *testing.T) {
+	input := []byte(`["short", "a much longer string"]`)
+	decoder := NewBytesDecoder(input)
+	decoder.LazyStringThreshold = 10
+	result, err := decoder.Decode()
+	require.Nil(t, err)
+
+	list := result.(*OJsonList).AsList()
+	short := list[0].(*OJsonString)
+	require.False(t, short.IsLazy())
+	require.Equal(t, "short", short.String())
+
+	long := list[1].(*OJsonString)
+	require.True(t, long.IsLazy())
+	// no copy, the value points into the input
+	require.Equal(t, &input[11], &long.lazyValue[0])
+	require.Equal(t, "[\n    \"short\",\n    \"a much longer string\"\n]\n", JSONString(result))
+	require.Empty(t, long.Value)
+	require.Equal(t, "a much longer string", long.String())
+	require.Equal(t, "a much longer string", long.Value)
+	require.False(t, long.IsLazy())
+
+	// replacing a lazy value before it was converted
+	decoder = NewBytesDecoder(input)
+	decoder.LazyStringThreshold = 10
+	result, err = decoder.Decode()
+	require.Nil(t, err)
+	long = result.(*OJsonList).AsList()[1].(*OJsonString)
+	long.SetValue("replaced")
+	require.False(t, long.IsLazy())
+	require.Equal(t, "[\n    \"short\",\n    \"replaced\"\n]\n", JSONString(result))
+
+	// assigning Value directly also replaces the lazy value
+	decoder = NewBytesDecoder(input)
+	decoder.LazyStringThreshold = 10
+	result, err = decoder.Decode()
+	require.Nil(t, err)
+	long = result.(*OJsonList).AsList()[1].(*OJsonString)
+	long.Value = "assigned"
+	require.False(t, long.IsLazy())
+	require.Equal(t, "assigned", long.String())
+	require.Equal(t, "[\n    \"short\",\n    \"assigned\"\n]\n", JSONString(result))
+
+	// decoders reading from a stream have no input to point into
+	decoder = NewDecoder(bytes.NewReader(input))
+	decoder.LazyStringThreshold = 10
+	result, err = decoder.Decode()
+	require.Nil(t, err)
+	require.False(t, result.(*OJsonList).AsList()[1].(*OJsonString).IsLazy())
+}
+
+func TestTokenizer(t *testing.T) {
+	tokenizer := NewTokenizer(strings.NewReader(` {"a" : [true]} `))
+	var kinds []TokenKind
+	for {
+		token, err := tokenizer.Next()
+		require.Nil(t, err)
+		kinds = append(kinds, token.Kind)
+		if token.Kind == TokenEOF {
+			break
+		}
+	}
+	require.Equal(t, []TokenKind{
+		TokenBeginMap, TokenString, TokenColon, TokenBeginList, TokenLiteral, TokenEndList, TokenEndMap, TokenEOF,
+	}, kinds)
+}
+
+func TestRelaxedMode(t *testing.T) {
+	input := `// top comment
+{
+    // about a
+    "a": "1", /* about b */
+    "b": [
+        // first step
+        {"c": true,},
+        "x",
+    ],
+    // nothing after this
+}
+`
+	_, err := ParseOrderedJSON([]byte(input))
+	require.NotNil(t, err)
+
+	result, err := ParseOrderedJSONRelaxed([]byte(input))
+	require.Nil(t, err)
+	resultMap := result.(*OJsonMap)
+	require.Equal(t, []string{"// top comment"}, resultMap.Comments)
+	require.Equal(t, []string{"// about a"}, resultMap.OrderedKV[0].Comments)
+	require.Equal(t, []string{"/* about b */"}, resultMap.OrderedKV[1].Comments)
+	require.Equal(t, []string{"// nothing after this"}, resultMap.EndComments)
+	list := resultMap.OrderedKV[1].Value.(*OJsonList).AsList()
+	require.Len(t, list, 2)
+	require.Equal(t, []string{"// first step"}, list[0].(*OJsonMap).Comments)
+
+	expected := `// top comment
+{
+    // about a
+    "a": "1",
+    /* about b */
+    "b": [
+        // first step
+        {
+            "c": true
+        },
+        "x"
+    ]
+    // nothing after this
+}
+`
+	written := JSONString(result)
+	require.Equal(t, expected, written)
+
+	// the written JSON is stable
+	reparsed, err := ParseOrderedJSONRelaxed([]byte(written))
+	require.Nil(t, err)
+	require.Equal(t, written, JSONString(reparsed))
+}
+
+// decodeRelaxed parses in relaxed mode, also yielding the comments of lists and bools.
+func decodeRelaxed(t *testing.T, input string) (OJsonObject, CommentTable) {
+	decoder := NewBytesDecoder([]byte(input))
+	decoder.Relaxed = true
+	result, err := decoder.Decode()
+	require.Nil(t, err)
+	return result, decoder.Comments
+}
+
+func writeWithComments(j OJsonObject, comments CommentTable) string {
+	options := DefaultFormatOptions()
+	options.Comments = comments
+	return JSONStringWithOptions(j, options)
+}
+
+func TestRelaxedModeCommentPlacement(t *testing.T) {
+	result, comments := decodeRelaxed(t, "{\"a\": [true // x\n], \"b\": \"1\"}")
+	resultMap := result.(*OJsonMap)
+	require.Equal(t, []string{"// x"}, comments[resultMap.OrderedKV[0].Value].EndComments)
+	require.Empty(t, resultMap.OrderedKV[1].Comments)
+	require.Equal(t, "{\n    \"a\": [\n        true\n        // x\n    ],\n    \"b\": \"1\"\n}\n", writeWithComments(result, comments))
+
+	result, comments = decodeRelaxed(t, `{"a": /* c */ true, "b": "1"}`)
+	resultMap = result.(*OJsonMap)
+	require.Equal(t, []string{"/* c */"}, comments[resultMap.OrderedKV[0].Value].Comments)
+	require.Empty(t, resultMap.OrderedKV[1].Comments)
+	require.Equal(t, "{\n    \"a\": /* c */\n    true,\n    \"b\": \"1\"\n}\n", writeWithComments(result, comments))
+
+	result, comments = decodeRelaxed(t, `["x"] // c`)
+	require.Equal(t, []string{"// c"}, comments[result].EndComments)
+	require.Equal(t, "[\n    \"x\"\n    // c\n]\n", writeWithComments(result, comments))
+
+	result, comments = decodeRelaxed(t, "[/* a */ [], [ // b\n], false /* c */]")
+	list := result.(*OJsonList).AsList()
+	require.Equal(t, []string{"/* a */"}, comments[list[0]].Comments)
+	require.Equal(t, []string{"// b"}, comments[list[1]].EndComments)
+	require.Equal(t, []string{"/* c */"}, comments[result].EndComments)
+
+	// without the comment table, only the comments of lists and bools are missing
+	require.Equal(t, "[\n    [],\n    [],\n    false\n]\n", JSONString(result))
+
+	// the written JSON keeps all the comments, in the same places
+	for _, input := range []string{
+		"{\"a\": [true // x\n], \"b\": \"1\"}",
+		`{"a": /* c */ true, "b": "1"}`,
+		`["x"] // c`,
+		"[/* a */ [], [ // b\n], false /* c */]",
+	} {
+		result, comments = decodeRelaxed(t, input)
+		written := writeWithComments(result, comments)
+		reparsed, reparsedComments := decodeRelaxed(t, written)
+		require.Equal(t, result, reparsed, input)
+		require.Equal(t, len(comments), len(reparsedComments), input)
+		require.Equal(t, written, writeWithComments(reparsed, reparsedComments), input)
+	}
+}
+
+func TestRelaxedModeErrors(t *testing.T) {
+	for _, input := range []string{
+		`{"a": "b" /* unterminated`,
+		`{"a": "b" / "c"}`,
+		`{"a": "b",,}`,
+		`[,]`,
+	} {
+		_, err := ParseOrderedJSONRelaxed([]byte(input))
+		require.NotNil(t, err, "input: %s", input)
+	}
+
+	result, err := ParseOrderedJSONRelaxed([]byte(`[true/**/, false// end`))
+	require.NotNil(t, err)
+	require.Nil(t, result)
+
+	result, err = ParseOrderedJSONRelaxed([]byte("[true/**/, false// end\n]"))
+	require.Nil(t, err)
+	require.Len(t, result.(*OJsonList).AsList(), 2)
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	input := "{\n    \"a\": \"1\",\n    \"a\": \"2\"\n}"
+	_, err := ParseOrderedJSON([]byte(input))
+	require.Equal(t, &DuplicateKeyError{Key: "a", Line: 3, Column: 5, FirstLine: 2, FirstColumn: 5}, err)
+
+	_, err = ParseOrderedJSONRelaxed([]byte(input))
+	require.Equal(t, &DuplicateKeyError{Key: "a", Line: 3, Column: 5, FirstLine: 2, FirstColumn: 5}, err)
+
+	decoder := NewDecoder(strings.NewReader(input))
+	decoder.AllowDuplicateKeys = true
+	result, err := decoder.Decode()
+	require.Nil(t, err)
+	require.Equal(t, []error{&DuplicateKeyError{Key: "a", Line: 3, Column: 5, FirstLine: 2, FirstColumn: 5}}, decoder.Warnings)
+	resultMap := result.(*OJsonMap)
+	require.Equal(t, 1, resultMap.Size())
+	require.Equal(t, "1", resultMap.OrderedKV[0].Value.(*OJsonString).String())
+
+	// same key in different maps is fine
+	_, err = ParseOrderedJSON([]byte(`{"a": {"a": "1"}, "b": {"a": "2"}}`))
+	require.Nil(t, err)
+}
+
+// benchmarkInput resembles a large scenario: many accounts, with storage and large hex code.
+func benchmarkInput() []byte {
+	var sb strings.Builder
+	sb.WriteString(`{"name": "benchmark", "accounts": {`)
+	for i := 0; i < 200; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf(`"0x%064x": {"nonce": "%d", "balance": "1,000,000", "storage": {`, i, i))
+		for j := 0; j < 20; j++ {
+			if j > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(fmt.Sprintf(`"''key%d": "0x%064x"`, j, j))
+		}
+		sb.WriteString(`}, "code": "0x` + strings.Repeat("0061736d01000000", 2000) + `"}`)
+	}
+	sb.WriteString(`}}`)
+	return []byte(sb.String())
+}
+
+func BenchmarkParseOrderedJSON(b *testing.B) {
+	input := benchmarkInput()
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := ParseOrderedJSON(input)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecoderLazyStrings(b *testing.B) {
+	input := benchmarkInput()
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		decoder := NewBytesDecoder(input)
+		decoder.LazyStringThreshold = 1024
+		_, err := decoder.Decode()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEncodingJSON is the reference: the standard library decoding the same input, without preserving order.
+func BenchmarkEncodingJSON(b *testing.B) {
+	input := benchmarkInput()
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var result interface{}
+		err := json.Unmarshal(input, &result)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
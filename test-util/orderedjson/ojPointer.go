@@ -0,0 +1,168 @@
+package orderedjson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The functions below locate values in the tree with JSON Pointers (RFC 6901), e.g. "/steps/3/tx/from".
+// Pointer tokens are matched against keys as they are stored in the tree, with "~1" standing for '/' and "~0" for '~'.
+
+// Get yields the value the pointer refers to. The empty pointer refers to the root.
+func Get(root OJsonObject, pointer string) (OJsonObject, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	current := root
+	for i, token := range tokens {
+		current, err = child(current, token)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pointerPrefix(tokens, i+1), err)
+		}
+	}
+	return current, nil
+}
+
+// Set replaces the value the pointer refers to, keeping its position.
+// If the last token is a new map key, the pair is added at the end of the map.
+// For lists, the index can also be the list length or "-", which appends the value.
+func Set(root OJsonObject, pointer string, value OJsonObject) error {
+	parent, lastToken, err := getParent(root, pointer)
+	if err != nil {
+		return err
+	}
+	switch container := parent.(type) {
+	case *OJsonMap:
+		for _, kvp := range container.OrderedKV {
+			if kvp.Key == lastToken {
+				kvp.Value = value
+				return nil
+			}
+		}
+		container.Put(lastToken, value)
+		return nil
+	case *OJsonList:
+		if lastToken == "-" {
+			*container = append(*container, value)
+			return nil
+		}
+		index, err := listIndex(lastToken, len(*container)+1)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pointer, err)
+		}
+		if index == len(*container) {
+			*container = append(*container, value)
+		} else {
+			(*container)[index] = value
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: parent is not a map or a list", pointer)
+	}
+}
+
+// Delete removes the value the pointer refers to, from its map or list.
+func Delete(root OJsonObject, pointer string) error {
+	parent, lastToken, err := getParent(root, pointer)
+	if err != nil {
+		return err
+	}
+	switch container := parent.(type) {
+	case *OJsonMap:
+		for i, kvp := range container.OrderedKV {
+			if kvp.Key == lastToken {
+				container.OrderedKV = append(container.OrderedKV[:i], container.OrderedKV[i+1:]...)
+				delete(container.KeySet, lastToken)
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: key not found", pointer)
+	case *OJsonList:
+		index, err := listIndex(lastToken, len(*container))
+		if err != nil {
+			return fmt.Errorf("%s: %w", pointer, err)
+		}
+		*container = append((*container)[:index], (*container)[index+1:]...)
+		return nil
+	default:
+		return fmt.Errorf("%s: parent is not a map or a list", pointer)
+	}
+}
+
+func getParent(root OJsonObject, pointer string) (OJsonObject, string, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(tokens) == 0 {
+		return nil, "", errors.New("the root cannot be replaced or deleted")
+	}
+	parent, err := Get(root, pointerPrefix(tokens, len(tokens)-1))
+	if err != nil {
+		return nil, "", err
+	}
+	return parent, tokens[len(tokens)-1], nil
+}
+
+func child(parent OJsonObject, token string) (OJsonObject, error) {
+	switch container := parent.(type) {
+	case *OJsonMap:
+		for _, kvp := range container.OrderedKV {
+			if kvp.Key == token {
+				return kvp.Value, nil
+			}
+		}
+		return nil, fmt.Errorf("key \"%s\" not found", token)
+	case *OJsonList:
+		index, err := listIndex(token, len(*container))
+		if err != nil {
+			return nil, err
+		}
+		return (*container)[index], nil
+	default:
+		return nil, errors.New("not a map or a list")
+	}
+}
+
+// listIndex parses a list index, which must be below the limit.
+func listIndex(token string, limit int) (int, error) {
+	// leading zeroes are not allowed by the RFC
+	if len(token) > 1 && token[0] == '0' {
+		return 0, fmt.Errorf("invalid list index \"%s\"", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid list index \"%s\"", token)
+	}
+	if index >= limit {
+		return 0, fmt.Errorf("list index %d out of range", index)
+	}
+	return index, nil
+}
+
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer \"%s\", it should start with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerPrefix rebuilds the pointer for the first tokens, for error messages and parent lookups.
+func pointerPrefix(tokens []string, length int) string {
+	var sb strings.Builder
+	for _, token := range tokens[:length] {
+		sb.WriteString("/")
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
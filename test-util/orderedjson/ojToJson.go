@@ -2,71 +2,288 @@ package orderedjson
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
-// JSONString returns a formatted string representation of an ordered JSON
+// FormatOptions configures how ordered JSON gets written.
+type FormatOptions struct {
+	// Indent is written once per nesting level, e.g. four spaces or a tab.
+	Indent string
+
+	// CompactWidth allows lists and maps to be written on a single line,
+	// if they fit within this many characters, indentation included.
+	// Values containing comments are never compacted. Zero disables compacting.
+	CompactWidth int
+
+	// SortKeys writes map keys in alphabetical order instead of their original order.
+	SortKeys bool
+
+	// Minify writes everything without any whitespace. Comments are dropped, since they could not be read back.
+	Minify bool
+
+	// Comments holds the comments of lists and bools, usually from Decoder.Comments. It can be nil.
+	Comments CommentTable
+}
+
+// DefaultFormatOptions yields the style of JSONString: four space indentation, every element on its own line.
+func DefaultFormatOptions() FormatOptions {
+	return FormatOptions{
+		Indent: "    ",
+	}
+}
+
+type jsonWriter struct {
+	sb      strings.Builder
+	options FormatOptions
+}
+
+// JSONString returns a formatted string representation of an ordered JSON.
+// Comments from the tree are written as well, each on its own line.
 func JSONString(j OJsonObject) string {
-	var sb strings.Builder
-	j.writeJSON(&sb, 0)
-	sb.WriteString("\n")
-	return sb.String()
+	return JSONStringWithOptions(j, DefaultFormatOptions())
+}
+
+// JSONStringWithOptions returns a string representation of an ordered JSON, formatted according to the options.
+func JSONStringWithOptions(j OJsonObject, options FormatOptions) string {
+	w := &jsonWriter{options: options}
+	if options.Minify {
+		j.writeJSON(w, 0)
+		return w.sb.String()
+	}
+	w.writeComments(w.leadingComments(j), 0)
+	w.writeValue(j, 0, 0)
+	w.sb.WriteString("\n")
+	return w.sb.String()
 }
 
-func addIndent(sb *strings.Builder, indent int) {
+func (w *jsonWriter) addIndent(indent int) {
 	for i := 0; i < indent; i++ {
-		sb.WriteString("    ")
+		w.sb.WriteString(w.options.Indent)
+	}
+}
+
+func (w *jsonWriter) newLine(indent int) {
+	if w.options.Minify {
+		return
+	}
+	w.sb.WriteString("\n")
+	w.addIndent(indent)
+}
+
+// leadingComments yields the comments before a value, from the node itself or from the comment table.
+func (w *jsonWriter) leadingComments(j OJsonObject) []string {
+	switch value := j.(type) {
+	case *OJsonMap:
+		return value.Comments
+	case *OJsonString:
+		return value.Comments
+	}
+	if comments := w.options.Comments.get(j); comments != nil {
+		return comments.Comments
+	}
+	return nil
+}
+
+// endComments yields the comments before the end of a list.
+func (w *jsonWriter) endComments(j *OJsonList) []string {
+	if comments := w.options.Comments.get(j); comments != nil {
+		return comments.EndComments
+	}
+	return nil
+}
+
+// writeComments writes each comment followed by a new line, so that the next element starts indented on the next line.
+func (w *jsonWriter) writeComments(comments []string, indent int) {
+	if w.options.Minify {
+		return
+	}
+	for _, comment := range comments {
+		w.sb.WriteString(comment)
+		w.newLine(indent)
+	}
+}
+
+// writeEndComments writes the comments after the last element of a map or list, each on a new line.
+func (w *jsonWriter) writeEndComments(comments []string, indent int) {
+	if w.options.Minify {
+		return
+	}
+	for _, comment := range comments {
+		w.newLine(indent)
+		w.sb.WriteString(comment)
+	}
+}
+
+// writeValue writes lists and maps on a single line if they are small enough, and normally otherwise.
+// The prefix length is the number of characters already on the line after the indentation, e.g. for the map key.
+func (w *jsonWriter) writeValue(j OJsonObject, indent int, prefixLength int) {
+	if w.options.CompactWidth > 0 && !w.options.Minify {
+		maxLength := w.options.CompactWidth - indent*len(w.options.Indent) - prefixLength
+		if singleLine, fits := w.singleLine(j, maxLength); fits {
+			w.sb.WriteString(singleLine)
+			return
+		}
+	}
+	j.writeJSON(w, indent)
+}
+
+// singleLine renders a value on one line, unless it is longer than maxLength or contains comments.
+func (w *jsonWriter) singleLine(j OJsonObject, maxLength int) (string, bool) {
+	if maxLength <= 0 || w.hasComments(j) {
+		return "", false
+	}
+	lineWriter := &jsonWriter{options: FormatOptions{SortKeys: w.options.SortKeys}}
+	lineWriter.writeSingleLine(j, maxLength)
+	if lineWriter.sb.Len() > maxLength {
+		return "", false
+	}
+	return lineWriter.sb.String(), true
+}
+
+func (w *jsonWriter) writeSingleLine(j OJsonObject, maxLength int) {
+	switch value := j.(type) {
+	case *OJsonMap:
+		w.sb.WriteString("{")
+		for i, child := range w.keyValuePairs(value) {
+			if w.sb.Len() > maxLength {
+				return
+			}
+			if i > 0 {
+				w.sb.WriteString(", ")
+			}
+			writeKey(&w.sb, child.Key)
+			w.sb.WriteString(" ")
+			w.writeSingleLine(child.Value, maxLength)
+		}
+		w.sb.WriteString("}")
+	case *OJsonList:
+		w.sb.WriteString("[")
+		for i, child := range value.AsList() {
+			if w.sb.Len() > maxLength {
+				return
+			}
+			if i > 0 {
+				w.sb.WriteString(", ")
+			}
+			w.writeSingleLine(child, maxLength)
+		}
+		w.sb.WriteString("]")
+	default:
+		j.writeJSON(w, 0)
 	}
 }
 
-func (j *OJsonMap) writeJSON(sb *strings.Builder, indent int) {
-	if j.Size() == 0 {
-		sb.WriteString("{}")
+func (w *jsonWriter) hasComments(j OJsonObject) bool {
+	if len(w.leadingComments(j)) > 0 {
+		return true
+	}
+	switch value := j.(type) {
+	case *OJsonMap:
+		if len(value.EndComments) > 0 {
+			return true
+		}
+		for _, child := range value.OrderedKV {
+			if len(child.Comments) > 0 || w.hasComments(child.Value) {
+				return true
+			}
+		}
+	case *OJsonList:
+		if len(w.endComments(value)) > 0 {
+			return true
+		}
+		for _, child := range value.AsList() {
+			if w.hasComments(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keyValuePairs yields the pairs in the order they should be written.
+func (w *jsonWriter) keyValuePairs(j *OJsonMap) []*OJsonKeyValuePair {
+	if !w.options.SortKeys {
+		return j.OrderedKV
+	}
+	sorted := make([]*OJsonKeyValuePair, len(j.OrderedKV))
+	copy(sorted, j.OrderedKV)
+	sort.SliceStable(sorted, func(i, k int) bool {
+		return sorted[i].Key < sorted[k].Key
+	})
+	return sorted
+}
+
+func writeKey(sb *strings.Builder, key string) {
+	sb.WriteString("\"")
+	sb.WriteString(key)
+	sb.WriteString("\":")
+}
+
+func (j *OJsonMap) writeJSON(w *jsonWriter, indent int) {
+	if j.Size() == 0 && (len(j.EndComments) == 0 || w.options.Minify) {
+		w.sb.WriteString("{}")
 		return
 	}
 
-	sb.WriteString("{")
-	for i, child := range j.OrderedKV {
-		sb.WriteString("\n")
-		addIndent(sb, indent+1)
-		sb.WriteString("\"")
-		sb.WriteString(child.Key)
-		sb.WriteString("\": ")
-		child.Value.writeJSON(sb, indent+1)
-		if i < len(j.OrderedKV)-1 {
-			sb.WriteString(",")
+	w.sb.WriteString("{")
+	keyValuePairs := w.keyValuePairs(j)
+	for i, child := range keyValuePairs {
+		w.newLine(indent + 1)
+		w.writeComments(child.Comments, indent+1)
+		writeKey(&w.sb, child.Key)
+		if !w.options.Minify {
+			w.sb.WriteString(" ")
+		}
+		w.writeComments(w.leadingComments(child.Value), indent+1)
+		// the key is only on the same line if there were no comments in between
+		prefixLength := 0
+		if len(w.leadingComments(child.Value)) == 0 {
+			prefixLength = len(child.Key) + 4
+		}
+		w.writeValue(child.Value, indent+1, prefixLength)
+		if i < len(keyValuePairs)-1 {
+			w.sb.WriteString(",")
 		}
 	}
-	sb.WriteString("\n")
-	addIndent(sb, indent)
-	sb.WriteString("}")
+	w.writeEndComments(j.EndComments, indent+1)
+	w.newLine(indent)
+	w.sb.WriteString("}")
 }
 
-func (j *OJsonList) writeJSON(sb *strings.Builder, indent int) {
+func (j *OJsonList) writeJSON(w *jsonWriter, indent int) {
 	collection := j.AsList()
-	if len(collection) == 0 {
-		sb.WriteString("[]")
+	endComments := w.endComments(j)
+	if len(collection) == 0 && (len(endComments) == 0 || w.options.Minify) {
+		w.sb.WriteString("[]")
 		return
 	}
 
-	sb.WriteString("[")
+	w.sb.WriteString("[")
 	for i, child := range collection {
-		sb.WriteString("\n")
-		addIndent(sb, indent+1)
-		child.writeJSON(sb, indent+1)
+		w.newLine(indent + 1)
+		w.writeComments(w.leadingComments(child), indent+1)
+		w.writeValue(child, indent+1, 0)
 		if i < len(collection)-1 {
-			sb.WriteString(",")
+			w.sb.WriteString(",")
 		}
 	}
-	sb.WriteString("\n")
-	addIndent(sb, indent)
-	sb.WriteString("]")
+	w.writeEndComments(endComments, indent+1)
+	w.newLine(indent)
+	w.sb.WriteString("]")
 }
 
-func (j *OJsonString) writeJSON(sb *strings.Builder, indent int) {
-	sb.WriteString(fmt.Sprintf("\"%s\"", j.Value))
+func (j *OJsonString) writeJSON(w *jsonWriter, indent int) {
+	w.sb.WriteString("\"")
+	if j.IsLazy() {
+		// no need to convert lazy strings just to write them
+		w.sb.Write(j.lazyValue)
+	} else {
+		w.sb.WriteString(j.Value)
+	}
+	w.sb.WriteString("\"")
 }
 
-func (j *OJsonBool) writeJSON(sb *strings.Builder, indent int) {
-	sb.WriteString(fmt.Sprintf("%v", bool(*j)))
+func (j *OJsonBool) writeJSON(w *jsonWriter, indent int) {
+	w.sb.WriteString(fmt.Sprintf("%v", bool(*j)))
 }
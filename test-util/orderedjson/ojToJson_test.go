@@ -0,0 +1,67 @@
+package orderedjson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const formatTestInput = `{"b": "1", "a": ["x", "y"], "c": {"d": true, "e": []}}`
+
+func TestFormatDefault(t *testing.T) {
+	result, err := ParseOrderedJSON([]byte(formatTestInput))
+	require.Nil(t, err)
+	require.Equal(t, JSONString(result), JSONStringWithOptions(result, DefaultFormatOptions()))
+	require.Equal(t, `{
+    "b": "1",
+    "a": [
+        "x",
+        "y"
+    ],
+    "c": {
+        "d": true,
+        "e": []
+    }
+}
+`, JSONString(result))
+}
+
+func TestFormatOptions(t *testing.T) {
+	result, err := ParseOrderedJSON([]byte(formatTestInput))
+	require.Nil(t, err)
+
+	require.Equal(t, `{"b":"1","a":["x","y"],"c":{"d":true,"e":[]}}`,
+		JSONStringWithOptions(result, FormatOptions{Minify: true}))
+
+	require.Equal(t, "{\n\t\"a\": [\"x\", \"y\"],\n\t\"b\": \"1\",\n\t\"c\": {\"d\": true, \"e\": []}\n}\n",
+		JSONStringWithOptions(result, FormatOptions{Indent: "\t", CompactWidth: 40, SortKeys: true}))
+
+	// everything fits on one line
+	require.Equal(t, "{\"b\": \"1\", \"a\": [\"x\", \"y\"], \"c\": {\"d\": true, \"e\": []}}\n",
+		JSONStringWithOptions(result, FormatOptions{Indent: "  ", CompactWidth: 100}))
+
+	// the key counts towards the width
+	require.Equal(t, `{
+  "b": "1",
+  "a": ["x", "y"],
+  "c": {
+    "d": true,
+    "e": []
+  }
+}
+`, JSONStringWithOptions(result, FormatOptions{Indent: "  ", CompactWidth: 20}))
+}
+
+func TestFormatCompactKeepsComments(t *testing.T) {
+	result, err := ParseOrderedJSONRelaxed([]byte(`{"a": [/* first */ "x", "y"], "b": ["z"]}`))
+	require.Nil(t, err)
+	require.Equal(t, `{
+    "a": [
+        /* first */
+        "x",
+        "y"
+    ],
+    "b": ["z"]
+}
+`, JSONStringWithOptions(result, FormatOptions{Indent: "    ", CompactWidth: 80}))
+}
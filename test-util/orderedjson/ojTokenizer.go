@@ -0,0 +1,305 @@
+package orderedjson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TokenKind is the type of a JSON token.
+type TokenKind int
+
+const (
+	// TokenEOF signals the end of the input.
+	TokenEOF TokenKind = iota
+
+	// TokenBeginMap is '{'.
+	TokenBeginMap
+
+	// TokenEndMap is '}'.
+	TokenEndMap
+
+	// TokenBeginList is '['.
+	TokenBeginList
+
+	// TokenEndList is ']'.
+	TokenEndList
+
+	// TokenColon is ':'.
+	TokenColon
+
+	// TokenComma is ','.
+	TokenComma
+
+	// TokenString is a string enclosed in quotes.
+	TokenString
+
+	// TokenLiteral is an unquoted value, e.g. true or false.
+	TokenLiteral
+)
+
+// Token is a lexical unit of a JSON input.
+type Token struct {
+	Kind TokenKind
+
+	// Value holds the raw contents of strings (without the quotes, escape sequences are not interpreted)
+	// and the text of literals. It is only valid until the next call to Tokenizer.Next.
+	Value []byte
+
+	// Offset is the position of the token in the input, in bytes.
+	Offset int64
+
+	// Line and Column locate the token in the input, for error messages. Both start at 1.
+	Line   int
+	Column int
+
+	// Comments holds the comments between the previous token and this one, including their delimiters.
+	// Only set when the tokenizer allows comments.
+	Comments []string
+}
+
+// Tokenizer splits a JSON input stream into tokens. It does not check that the tokens form valid JSON, the Decoder does.
+type Tokenizer struct {
+	reader    *bufio.Reader
+	offset    int64
+	line      int   // number of new lines consumed so far
+	lineStart int64 // offset of the beginning of the current line
+	buffer    []byte
+	comments  []string
+
+	// AllowComments enables "//" line comments and "/* */" block comments between tokens.
+	AllowComments bool
+}
+
+// NewTokenizer creates a tokenizer that reads from the given input. The input gets buffered internally.
+func NewTokenizer(reader io.Reader) *Tokenizer {
+	return &Tokenizer{
+		reader: bufio.NewReaderSize(reader, 64*1024),
+	}
+}
+
+// Offset yields the number of bytes consumed so far.
+func (t *Tokenizer) Offset() int64 {
+	return t.offset
+}
+
+// Next reads the next token. At the end of the input it yields a TokenEOF token.
+func (t *Tokenizer) Next() (Token, error) {
+	token, err := t.next()
+	if err != nil {
+		return Token{}, err
+	}
+	token.Line = t.line + 1
+	token.Column = int(token.Offset-t.lineStart) + 1
+	token.Comments = t.comments
+	t.comments = nil
+	return token, nil
+}
+
+// consume keeps track of the position in the input, for data read from the reader.
+func (t *Tokenizer) consume(data []byte) {
+	newLines := bytes.Count(data, []byte{'\n'})
+	if newLines > 0 {
+		t.line += newLines
+		t.lineStart = t.offset + int64(bytes.LastIndexByte(data, '\n')) + 1
+	}
+	t.offset += int64(len(data))
+}
+
+func (t *Tokenizer) consumeByte(c byte) {
+	t.offset++
+	if c == '\n' {
+		t.line++
+		t.lineStart = t.offset
+	}
+}
+
+func (t *Tokenizer) next() (Token, error) {
+	c, err := t.skipWhitespace()
+	if err == io.EOF {
+		return Token{Kind: TokenEOF, Offset: t.offset}, nil
+	}
+	if err != nil {
+		return Token{}, err
+	}
+
+	offset := t.offset - 1
+	switch c {
+	case '{':
+		return Token{Kind: TokenBeginMap, Offset: offset}, nil
+	case '}':
+		return Token{Kind: TokenEndMap, Offset: offset}, nil
+	case '[':
+		return Token{Kind: TokenBeginList, Offset: offset}, nil
+	case ']':
+		return Token{Kind: TokenEndList, Offset: offset}, nil
+	case ':':
+		return Token{Kind: TokenColon, Offset: offset}, nil
+	case ',':
+		return Token{Kind: TokenComma, Offset: offset}, nil
+	case '"':
+		value, err := t.readString()
+		if err != nil {
+			return Token{}, err
+		}
+		return Token{Kind: TokenString, Value: value, Offset: offset}, nil
+	default:
+		value, err := t.readLiteral(c)
+		if err != nil {
+			return Token{}, err
+		}
+		return Token{Kind: TokenLiteral, Value: value, Offset: offset}, nil
+	}
+}
+
+// skipWhitespace also skips comments, if allowed, and collects them.
+func (t *Tokenizer) skipWhitespace() (byte, error) {
+	for {
+		c, err := t.reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		t.consumeByte(c)
+		if c == '/' && t.AllowComments {
+			err = t.readComment()
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if !isWhitespace(c) {
+			return c, nil
+		}
+	}
+}
+
+// readComment reads a comment, after the initial '/'.
+func (t *Tokenizer) readComment() error {
+	c, err := t.reader.ReadByte()
+	if err != nil {
+		return errors.New("invalid comment")
+	}
+	t.consumeByte(c)
+	switch c {
+	case '/':
+		line, err := t.reader.ReadString('\n')
+		t.consume([]byte(line))
+		if err != nil && err != io.EOF {
+			return err
+		}
+		t.comments = append(t.comments, "//"+strings.TrimRight(line, "\r\n"))
+		return nil
+	case '*':
+		t.buffer = append(t.buffer[:0], "/*"...)
+		for {
+			chunk, err := t.reader.ReadSlice('/')
+			t.consume(chunk)
+			t.buffer = append(t.buffer, chunk...)
+			if err == bufio.ErrBufferFull {
+				continue
+			}
+			if err != nil {
+				return errors.New("unterminated comment")
+			}
+			// the "/*" at the beginning does not count as the end
+			if len(t.buffer) >= 4 && t.buffer[len(t.buffer)-2] == '*' {
+				t.comments = append(t.comments, string(t.buffer))
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("invalid comment at offset %d", t.offset-2)
+	}
+}
+
+// readString reads everything up to the closing quote, which is consumed but not included.
+// Quotes preceded by an odd number of backslashes are escaped and do not end the string.
+func (t *Tokenizer) readString() ([]byte, error) {
+	t.buffer = t.buffer[:0]
+	for {
+		// ReadSlice finds the next quote without copying byte by byte
+		chunk, err := t.reader.ReadSlice('"')
+		t.consume(chunk)
+		t.buffer = append(t.buffer, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF {
+			return nil, errors.New("unterminated string")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		contentLength := len(t.buffer) - 1
+		if precedingBackslashes(t.buffer[:contentLength])%2 == 0 {
+			t.buffer = t.buffer[:contentLength]
+			return t.buffer, nil
+		}
+	}
+}
+
+func precedingBackslashes(data []byte) int {
+	count := 0
+	for i := len(data) - 1; i >= 0 && data[i] == '\\'; i-- {
+		count++
+	}
+	return count
+}
+
+// readLiteral reads an unquoted value, up to the next delimiter or whitespace.
+func (t *Tokenizer) readLiteral(first byte) ([]byte, error) {
+	t.buffer = append(t.buffer[:0], first)
+	for {
+		c, err := t.reader.ReadByte()
+		if err == io.EOF {
+			return t.buffer, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isWhitespace(c) || isDelimiter(c) || (c == '/' && t.AllowComments) {
+			_ = t.reader.UnreadByte()
+			return t.buffer, nil
+		}
+		t.consumeByte(c)
+		t.buffer = append(t.buffer, c)
+	}
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t'
+}
+
+func isDelimiter(c byte) bool {
+	return c == '{' || c == '}' || c == '[' || c == ']' || c == ':' || c == ',' || c == '"'
+}
+
+// String yields a readable description of the token kind, for error messages.
+func (k TokenKind) String() string {
+	switch k {
+	case TokenEOF:
+		return "end of input"
+	case TokenBeginMap:
+		return "'{'"
+	case TokenEndMap:
+		return "'}'"
+	case TokenBeginList:
+		return "'['"
+	case TokenEndList:
+		return "']'"
+	case TokenColon:
+		return "':'"
+	case TokenComma:
+		return "','"
+	case TokenString:
+		return "string"
+	case TokenLiteral:
+		return "literal"
+	default:
+		return fmt.Sprintf("TokenKind(%d)", int(k))
+	}
+}
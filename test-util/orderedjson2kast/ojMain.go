@@ -6,20 +6,28 @@ import (
 	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
 )
 
-// ProcessCodeFunc represents a callback to assemble the code in the test
+// ProcessCodeFunc represents a callback to assemble the code in the test.
+// It receives the test directory and the code value, e.g. "file:../output/adder.wasm".
 type ProcessCodeFunc func(testPath string, value string) string
 
 // ConvertOrderedJSONToKast parses data as an ordered JSON,
 // assembles code if necessary
-// and converts to KAST format, readable by K
+// and converts to KAST format, readable by K.
+// The output is for the IELE semantics.
 func ConvertOrderedJSONToKast(data []byte, testFilePath string, processCodeCallback ProcessCodeFunc) (string, error) {
+	return ConvertOrderedJSONToKastWithProfile(data, testFilePath, processCodeCallback, IELEProfile())
+}
+
+// ConvertOrderedJSONToKastWithProfile is like ConvertOrderedJSONToKast,
+// but writes the KAST for the semantics described by the profile.
+func ConvertOrderedJSONToKastWithProfile(data []byte, testFilePath string, processCodeCallback ProcessCodeFunc, profile KastProfile) (string, error) {
 	jsonObj, err := oj.ParseOrderedJSON(data)
 	if err != nil {
 		return "", err
 	}
 	testDirPath := filepath.Dir(testFilePath)
 	processTestCode(jsonObj, testDirPath, processCodeCallback)
-	kast := jsonToKastOrdered(jsonObj)
+	kast := jsonToKastOrdered(jsonObj, profile)
 
 	return kast, nil
 }
@@ -0,0 +1,105 @@
+package orderedjson2kast
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the expected .kast files in testdata")
+
+// markCode replaces the code with something recognizable, that also records where the code was resolved from.
+func markCode(testPath string, value string) string {
+	return "assembled(" + testPath + ", " + value + ")"
+}
+
+func TestConvertGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	require.Nil(t, err)
+	require.NotEmpty(t, inputs)
+
+	for _, profile := range []KastProfile{IELEProfile(), JSONProfile()} {
+		for _, input := range inputs {
+			data, err := os.ReadFile(input)
+			require.Nil(t, err)
+			kast, err := ConvertOrderedJSONToKastWithProfile(data, input, markCode, profile)
+			require.Nil(t, err, input)
+
+			goldenPath := input + "." + profile.Name + ".kast"
+			if *updateGolden {
+				require.Nil(t, os.WriteFile(goldenPath, []byte(kast+"\n"), 0644))
+				continue
+			}
+			expected, err := os.ReadFile(goldenPath)
+			require.Nil(t, err)
+			require.Equal(t, string(expected), kast+"\n", goldenPath)
+		}
+	}
+}
+
+func TestProcessScenarioCode(t *testing.T) {
+	input := `{"steps": [
+		{"step": "scDeploy", "tx": {"contractCode": "file:a.wasm"}},
+		{"step": "scCall", "tx": {"to": "''sc", "function": "f"}},
+		{"step": "checkState", "accounts": {"''a": {"code": "*"}, "''b": {"code": ""}, "+": ""}},
+		{"step": "externalSteps", "path": "other.steps.json"}
+	]}`
+	var processed []string
+	kast, err := ConvertOrderedJSONToKast([]byte(input), "dir/test.scen.json", func(testPath string, value string) string {
+		require.Equal(t, "dir", testPath)
+		processed = append(processed, value)
+		return "0x00"
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"file:a.wasm"}, processed)
+	require.Contains(t, kast, `#token("\"0x00\"","String")`)
+	require.Contains(t, kast, `#token("\"dir/other.steps.json\"","String")`)
+}
+
+func TestStringKastEscaping(t *testing.T) {
+	kast, err := ConvertOrderedJSONToKast([]byte(`["plain", "quote \" backslash \\"]`), "test.json", markCode)
+	require.Nil(t, err)
+	expected := "`[_]_IELE-DATA`(" +
+		"`_,__IELE-DATA`(" + `#token("\"plain\"","String")` + "," +
+		"`_,__IELE-DATA`(" + `#token("\"quote \\\" backslash \\\\\"","String")` + "," +
+		"`.List{\"_,__IELE-DATA\"}`(.KList))))"
+	require.Equal(t, expected, kast)
+}
+
+func TestKastProfiles(t *testing.T) {
+	input := []byte(`{"a": [true]}`)
+	kast, err := ConvertOrderedJSONToKastWithProfile(input, "test.json", markCode, JSONProfile())
+	require.Nil(t, err)
+	require.Equal(t, "`JSONObject`(`JSONs`(`JSONEntry`("+`#token("\"a\"","String")`+","+
+		"`JSONList`(`JSONs`("+`#token("true","Bool")`+",`.List{\"JSONs\"}`(.KList)))),"+
+		"`.List{\"JSONs\"}`(.KList)))", kast)
+
+	profile, err := KastProfileByName("iele")
+	require.Nil(t, err)
+	require.Equal(t, IELEProfile(), profile)
+	_, err = KastProfileByName("unknown")
+	require.NotNil(t, err)
+}
+
+func TestKastProfileTokens(t *testing.T) {
+	profile := KastProfile{
+		Name:          "custom",
+		MapLabel:      "`object`",
+		ListLabel:     "`list`",
+		EntryLabel:    "`entry`",
+		ConsLabel:     "`cons`",
+		EmptyLabel:    "`nil`",
+		StringSort:    "Id",
+		StringQuoting: RawTokens,
+		TrueLabel:     "`true`",
+		FalseLabel:    "`false`",
+	}
+	kast, err := ConvertOrderedJSONToKastWithProfile([]byte(`{"a \"b\"": [true, false]}`), "test.json", markCode, profile)
+	require.Nil(t, err)
+	require.Equal(t, "`object`(`cons`(`entry`("+`#token("a \\\"b\\\"","Id")`+","+
+		"`list`(`cons`(`true`(.KList),`cons`(`false`(.KList),`nil`(.KList))))),"+
+		"`nil`(.KList)))", kast)
+}
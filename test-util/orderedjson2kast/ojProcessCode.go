@@ -1,17 +1,101 @@
 package orderedjson2kast
 
 import (
+	"path/filepath"
+
+	mj "github.com/kalyan3104/dme-vm-util/test-util/mandos/json/model"
 	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
 )
 
 func processTestCode(jobj oj.OJsonObject, testPath string, processCodeCallback ProcessCodeFunc) {
+	if steps, isScenario := scenarioSteps(jobj); isScenario {
+		for _, step := range steps {
+			processScenarioStep(step, testPath, processCodeCallback)
+		}
+		return
+	}
+	processLegacyTestCode(jobj, testPath, processCodeCallback)
+}
+
+// scenarioSteps yields the steps list, if the object is a scenario.
+func scenarioSteps(jobj oj.OJsonObject) ([]oj.OJsonObject, bool) {
+	scenarioMap, isMap := jobj.(*oj.OJsonMap)
+	if !isMap {
+		return nil, false
+	}
+	stepsList, isList := mapValue(scenarioMap, "steps").(*oj.OJsonList)
+	if !isList {
+		return nil, false
+	}
+	return stepsList.AsList(), true
+}
+
+func processScenarioStep(jobj oj.OJsonObject, testPath string, processCodeCallback ProcessCodeFunc) {
+	stepMap, isMap := jobj.(*oj.OJsonMap)
+	if !isMap {
+		return
+	}
+	stepType, isStr := mapValue(stepMap, "step").(*oj.OJsonString)
+	if !isStr {
+		return
+	}
+
+	switch stepType.String() {
+	case mj.StepNameSetState, mj.StepNameCheckState:
+		accounts, isMap := mapValue(stepMap, "accounts").(*oj.OJsonMap)
+		if !isMap {
+			return
+		}
+		for _, accountKVP := range accounts.OrderedKV {
+			if account, isMap := accountKVP.Value.(*oj.OJsonMap); isMap {
+				processCodeValue(mapValue(account, "code"), testPath, processCodeCallback)
+			}
+		}
+	case mj.StepNameScDeploy:
+		if tx, isMap := mapValue(stepMap, "tx").(*oj.OJsonMap); isMap {
+			processCodeValue(mapValue(tx, "contractCode"), testPath, processCodeCallback)
+		}
+	case mj.StepNameExternalSteps:
+		// the external steps get loaded from wherever the KAST is run, so the path cannot stay relative to the scenario
+		path, isStr := mapValue(stepMap, "path").(*oj.OJsonString)
+		if isStr && !filepath.IsAbs(path.String()) {
+			path.SetValue(filepath.Join(testPath, path.String()))
+		}
+	}
+}
+
+// processCodeValue hands the code to the callback. Empty code and the "*" check wildcard are left as they are.
+func processCodeValue(jobj oj.OJsonObject, testPath string, processCodeCallback ProcessCodeFunc) {
+	strVal, isStr := jobj.(*oj.OJsonString)
+	if !isStr {
+		return
+	}
+	code := strVal.String()
+	if code == "" || code == "*" {
+		return
+	}
+	strVal.SetValue(processCodeCallback(testPath, code))
+}
+
+// mapValue yields the value for a key, or nil if the key is missing.
+func mapValue(j *oj.OJsonMap, key string) oj.OJsonObject {
+	for _, keyValuePair := range j.OrderedKV {
+		if keyValuePair.Key == key {
+			return keyValuePair.Value
+		}
+	}
+	return nil
+}
+
+// processLegacyTestCode handles the old .test.json layout, where deploys are the transactions with an empty "to".
+func processLegacyTestCode(jobj oj.OJsonObject, testPath string, processCodeCallback ProcessCodeFunc) {
 	switch j := jobj.(type) {
 	case *oj.OJsonMap:
 		isCreateTx := false
 		for _, keyValuePair := range j.OrderedKV {
 			if keyValuePair.Key == "to" {
 				if strVal, isStr := keyValuePair.Value.(*oj.OJsonString); isStr {
-					if strVal.Value == "" {
+					if strVal.String() == "" {
 						isCreateTx = true
 						break
 					}
@@ -23,16 +107,16 @@ func processTestCode(jobj oj.OJsonObject, testPath string, processCodeCallback P
 			if keyValuePair.Key == "code" ||
 				(keyValuePair.Key == "contractCode" && isCreateTx) {
 				if strVal, isStr := keyValuePair.Value.(*oj.OJsonString); isStr {
-					strVal.Value = processCodeCallback(testPath, strVal.Value)
+					strVal.SetValue(processCodeCallback(testPath, strVal.String()))
 				}
 			} else {
-				processTestCode(keyValuePair.Value, testPath, processCodeCallback)
+				processLegacyTestCode(keyValuePair.Value, testPath, processCodeCallback)
 			}
 		}
 	case *oj.OJsonList:
-		collection := []oj.OJsonObject(*j)
+		collection := j.AsList()
 		for _, elem := range collection {
-			processTestCode(elem, testPath, processCodeCallback)
+			processLegacyTestCode(elem, testPath, processCodeCallback)
 		}
 	default:
 	}
@@ -7,51 +7,160 @@ import (
 	oj "github.com/kalyan3104/dme-vm-util/test-util/orderedjson"
 )
 
-func jsonToKastOrdered(j oj.OJsonObject) string {
+// TokenQuoting decides what the text of a string token is.
+type TokenQuoting int
+
+const (
+	// QuotedTokens write K string literals, quotes included, which is what the builtin String sort expects.
+	QuotedTokens TokenQuoting = iota
+
+	// RawTokens write the string value alone, for sorts whose tokens are not quoted.
+	RawTokens
+)
+
+// KastProfile describes how JSON gets written as KAST for a particular K semantics:
+// the labels of the JSON constructors and how strings and booleans become tokens.
+type KastProfile struct {
+	// Name identifies the profile, e.g. on the command line.
+	Name string
+
+	// MapLabel wraps the entries of a JSON object.
+	MapLabel string
+
+	// ListLabel wraps the elements of a JSON list.
+	ListLabel string
+
+	// EntryLabel builds a key-value pair of an object.
+	EntryLabel string
+
+	// ConsLabel and EmptyLabel build the K list of entries or elements.
+	ConsLabel  string
+	EmptyLabel string
+
+	// StringSort and BoolSort are the token sorts for strings, booleans and keys.
+	StringSort string
+	BoolSort   string
+
+	// StringQuoting is the text of the string tokens.
+	StringQuoting TokenQuoting
+
+	// TrueLabel and FalseLabel, if set, write booleans as constants instead of BoolSort tokens.
+	TrueLabel  string
+	FalseLabel string
+}
+
+// IELEProfile yields the profile for the IELE semantics, which define JSON in the IELE-DATA module.
+func IELEProfile() KastProfile {
+	return KastProfile{
+		Name:       "iele",
+		MapLabel:   "`{_}_IELE-DATA`",
+		ListLabel:  "`[_]_IELE-DATA`",
+		EntryLabel: "`_:__IELE-DATA`",
+		ConsLabel:  "`_,__IELE-DATA`",
+		EmptyLabel: "`.List{\"_,__IELE-DATA\"}`",
+		StringSort: "String",
+		BoolSort:   "Bool",
+	}
+}
+
+// JSONProfile yields the profile for semantics using the JSON module of the K standard library.
+func JSONProfile() KastProfile {
+	return KastProfile{
+		Name:       "json",
+		MapLabel:   "`JSONObject`",
+		ListLabel:  "`JSONList`",
+		EntryLabel: "`JSONEntry`",
+		ConsLabel:  "`JSONs`",
+		EmptyLabel: "`.List{\"JSONs\"}`",
+		StringSort: "String",
+		BoolSort:   "Bool",
+	}
+}
+
+// KastProfileByName yields one of the predefined profiles.
+func KastProfileByName(name string) (KastProfile, error) {
+	for _, profile := range []KastProfile{IELEProfile(), JSONProfile()} {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+	return KastProfile{}, fmt.Errorf("unknown KAST profile \"%s\"", name)
+}
+
+func jsonToKastOrdered(j oj.OJsonObject, profile KastProfile) string {
 	var sb strings.Builder
-	writeKast(j, &sb)
+	writeKast(j, &sb, profile)
 	return sb.String()
 }
 
-func writeStringKast(sb *strings.Builder, value string) {
-	sb.WriteString(fmt.Sprintf("#token(\"\\\"%s\\\"\",\"String\")", value))
+// kastTokenEscaper escapes the token text, so it can be placed between the quotes of a #token.
+var kastTokenEscaper = strings.NewReplacer("\\", "\\\\", "\"", "\\\"")
+
+// writeStringKast writes a K string token.
+// The value is kept as it was in the JSON, escape sequences included, since K strings use the same escapes.
+// For quoted tokens, the K string literal, quotes included, then gets escaped once more as the token text.
+func writeStringKast(sb *strings.Builder, value string, profile KastProfile) {
+	if profile.StringQuoting == QuotedTokens {
+		value = "\"" + value + "\""
+	}
+	writeTokenKast(sb, value, profile.StringSort)
+}
+
+// writeBoolKast writes a boolean as a token, or as a constant if the profile has labels for them.
+func writeBoolKast(sb *strings.Builder, value bool, profile KastProfile) {
+	label := profile.FalseLabel
+	if value {
+		label = profile.TrueLabel
+	}
+	if label != "" {
+		sb.WriteString(label + "(.KList)")
+		return
+	}
+	writeTokenKast(sb, fmt.Sprintf("%t", value), profile.BoolSort)
+}
+
+func writeTokenKast(sb *strings.Builder, text string, sort string) {
+	sb.WriteString("#token(\"")
+	sb.WriteString(kastTokenEscaper.Replace(text))
+	sb.WriteString("\",\"")
+	sb.WriteString(sort)
+	sb.WriteString("\")")
 }
 
-func writeKast(jobj oj.OJsonObject, sb *strings.Builder) {
+func writeKast(jobj oj.OJsonObject, sb *strings.Builder, profile KastProfile) {
 	switch j := jobj.(type) {
 	case *oj.OJsonMap:
-		sb.WriteString("`{_}_IELE-DATA`(")
+		sb.WriteString(profile.MapLabel + "(")
 		for _, keyValuePair := range j.OrderedKV {
-			sb.WriteString("`_,__IELE-DATA`(`_:__IELE-DATA`(")
-			writeStringKast(sb, keyValuePair.Key)
+			sb.WriteString(profile.ConsLabel + "(" + profile.EntryLabel + "(")
+			writeStringKast(sb, keyValuePair.Key, profile)
 			sb.WriteString(",")
-			writeKast(keyValuePair.Value, sb)
+			writeKast(keyValuePair.Value, sb, profile)
 			sb.WriteString("),")
 		}
-		sb.WriteString("`.List{\"_,__IELE-DATA\"}`(.KList)")
+		sb.WriteString(profile.EmptyLabel + "(.KList)")
 		for i := 0; i < j.Size(); i++ {
 			sb.WriteString(")")
 		}
 		sb.WriteString(")")
 	case *oj.OJsonList:
-		collection := []oj.OJsonObject(*j)
+		collection := j.AsList()
 
-		sb.WriteString("`[_]_IELE-DATA`(")
+		sb.WriteString(profile.ListLabel + "(")
 		for _, elem := range collection {
-			sb.WriteString("`_,__IELE-DATA`(")
-			writeKast(elem, sb)
+			sb.WriteString(profile.ConsLabel + "(")
+			writeKast(elem, sb, profile)
 			sb.WriteString(",")
 		}
-		sb.WriteString("`.List{\"_,__IELE-DATA\"}`(.KList)")
+		sb.WriteString(profile.EmptyLabel + "(.KList)")
 		for i := 0; i < len(collection); i++ {
 			sb.WriteString(")")
 		}
 		sb.WriteString(")")
 	case *oj.OJsonString:
-		writeStringKast(sb, j.Value)
+		writeStringKast(sb, j.String(), profile)
 	case *oj.OJsonBool:
-		value := bool(*j)
-		sb.WriteString(fmt.Sprintf("#token(\"%t\",\"Bool\")", value))
+		writeBoolKast(sb, bool(*j), profile)
 	default:
 		panic("unknown OJsonObject type")
 	}